@@ -0,0 +1,451 @@
+package op
+
+import "github.com/foreveralonet/trx"
+
+// FoldLeft folds the values of the source channel from left to right into a single accumulated value.
+// The accumulator starts at seed and is updated with each value as soon as it arrives, so the source
+// is consumed in a streaming fashion. Once the source is closed, the final accumulated value is emitted.
+// If an error is received from the source, it is sent downstream wrapped in a trx.Result and folding stops.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	A - The type of the accumulated value.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	seed   - The initial accumulated value.
+//	f      - A function that combines the current accumulated value with the next value.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[A] that emits the final accumulated value or an error.
+//
+// Example usage:
+//
+//	out := FoldLeft(source, 0, func(acc int, v int) int {
+//	    return acc - v // ((seed - v0) - v1) - v2 ...
+//	})
+func FoldLeft[T, A any](source <-chan trx.Result[T], seed A, f func(acc A, value T) A, options ...Option) <-chan trx.Result[A] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[A](conf)
+
+	go func() {
+		defer close(out)
+
+		acc := seed
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[A](err)
+
+					return
+				}
+
+				acc = f(acc, value)
+			}
+		}
+
+		out <- trx.Ok(acc)
+	}()
+
+	return out
+}
+
+// FoldRight folds the values of the source channel from right to left into a single accumulated value.
+// Because the last value must be combined first, FoldRight has to drain the whole source and keep every
+// value in memory before folding can start. Use it only with bounded sources; on a source that never
+// closes it will never emit. If an error is received from the source, it is sent downstream wrapped
+// in a trx.Result and folding is aborted.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	A - The type of the accumulated value.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing a bounded input stream.
+//	seed   - The initial accumulated value, combined with the last value first.
+//	f      - A function that combines a value with the accumulated value of everything to its right.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[A] that emits the final accumulated value or an error.
+//
+// Example usage:
+//
+//	out := FoldRight(source, 0, func(v int, acc int) int {
+//	    return v - acc // v0 - (v1 - (v2 - seed))
+//	})
+func FoldRight[T, A any](source <-chan trx.Result[T], seed A, f func(value T, acc A) A, options ...Option) <-chan trx.Result[A] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[A](conf)
+
+	go func() {
+		defer close(out)
+
+		values := make([]T, 0)
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[A](err)
+
+					return
+				}
+
+				values = append(values, value)
+			}
+		}
+
+		acc := seed
+		for i := len(values) - 1; i >= 0; i-- {
+			acc = f(values[i], acc)
+		}
+
+		out <- trx.Ok(acc)
+	}()
+
+	return out
+}
+
+// CountByKey counts how many values of the source channel fall under each key returned by keyFunc and emits
+// the final counts once the source is closed, like a histogram. An empty source emits an empty map.
+// If an error is received from the source, it is sent downstream wrapped in a trx.Result and counting stops.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	K - The type of the keys (must be comparable).
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	keyFunc - A function that returns the key of a value.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[map[K]int] that emits the count per key or an error.
+//
+// Example usage:
+//
+//	out := CountByKey(words, strings.ToLower)
+func CountByKey[T any, K comparable](source <-chan trx.Result[T], keyFunc func(value T) K, options ...Option) <-chan trx.Result[map[K]int] {
+	return FoldLeft(source, make(map[K]int), func(counts map[K]int, value T) map[K]int {
+		counts[keyFunc(value)]++
+
+		return counts
+	}, options...)
+}
+
+// RollingReduce maintains a sliding window of the last windowSize values from the source channel and, after
+// each new value, emits the reduction of the current window, starting from seed and folding from the oldest
+// to the newest value. Until the window is full, the reduction covers all values received so far.
+// If an error is received from the source, it is sent downstream wrapped in a trx.Result and reduction stops.
+//
+// The reduction is recomputed over the whole window for every value, so each emission costs windowSize
+// reducer calls. This works for any reducer, including non-invertible ones such as max; for invertible
+// aggregates such as a sum over a large window, a dedicated incremental computation is cheaper.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	A - The type of the reduced value.
+//
+// Parameters:
+//
+//	source     - A receive-only channel of trx.Result[T] representing the input stream.
+//	windowSize - The number of most recent values in the window. Values less than 1 are treated as 1.
+//	seed       - The initial value of each reduction.
+//	reducer    - A function that combines the current reduced value with the next value in the window.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[A] that emits the reduction of the window after each value, or an error.
+//
+// Example usage:
+//
+//	out := RollingReduce(source, 3, 0, func(acc int, v int) int {
+//	    return acc + v // Rolling sum of the last 3 values
+//	})
+func RollingReduce[T, A any](source <-chan trx.Result[T], windowSize int, seed A, reducer func(acc A, value T) A, options ...Option) <-chan trx.Result[A] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[A](conf)
+	windowSize = max(windowSize, 1)
+
+	go func() {
+		defer close(out)
+
+		window := make([]T, 0, windowSize)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[A](err)
+
+					return
+				}
+
+				if len(window) == windowSize {
+					copy(window, window[1:])
+					window = window[:windowSize-1]
+				}
+				window = append(window, value)
+
+				acc := seed
+				for _, w := range window {
+					acc = reducer(acc, w)
+				}
+
+				out <- trx.Ok(acc)
+			}
+		}
+	}()
+
+	return out
+}
+
+// Accumulate applies step to each value of the source channel, starting from seed, and emits a trx.Step after
+// every value carrying both the new accumulated state and the input that produced it, so downstream stages
+// see the running state together with its cause. If an error is received from the source or returned by
+// step, it is sent downstream wrapped in a trx.Result and accumulation stops.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	A - The type of the accumulated state.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	seed   - The initial accumulated state.
+//	step   - A function that combines the current state with the next value, possibly returning an error.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[trx.Step[A, T]] that emits each intermediate step or an error.
+//
+// Example usage:
+//
+//	out := Accumulate(deposits, 0, func(balance int, amount int) (int, error) {
+//	    return balance + amount, nil
+//	})
+func Accumulate[T, A any](source <-chan trx.Result[T], seed A, step func(acc A, value T) (A, error), options ...Option) <-chan trx.Result[trx.Step[A, T]] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[trx.Step[A, T]](conf)
+
+	go func() {
+		defer close(out)
+
+		acc := seed
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[trx.Step[A, T]](err)
+
+					return
+				}
+
+				acc, err = step(acc, value)
+				if err != nil {
+					out <- trx.Err[trx.Step[A, T]](err)
+
+					return
+				}
+
+				out <- trx.Ok(trx.Step[A, T]{State: acc, Input: value})
+			}
+		}
+	}()
+
+	return out
+}
+
+// Reduce folds the values of the source channel into a single value, starting from seed, and emits it once
+// the source is closed. It is the final-value counterpart of Accumulate, and unlike FoldLeft the accumulator
+// receives the index of each value and may fail. If the source is empty, seed is emitted. If an error is
+// received from the source or returned by accumulator, it is sent downstream wrapped in a trx.Result and
+// reduction stops.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of the accumulated value.
+//
+// Parameters:
+//
+//	source      - A receive-only channel of trx.Result[T] representing the input stream.
+//	seed        - The initial accumulated value.
+//	accumulator - A function that combines the current accumulated value with the next value and its index.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] that emits the final accumulated value or an error.
+//
+// Example usage:
+//
+//	out := Reduce(orders, 0.0, func(total float64, o Order, _ int) (float64, error) {
+//	    if o.Amount < 0 {
+//	        return 0, fmt.Errorf("order %s: negative amount", o.ID)
+//	    }
+//	    return total + o.Amount, nil
+//	})
+func Reduce[T, U any](source <-chan trx.Result[T], seed U, accumulator func(acc U, value T, index int) (U, error), options ...Option) <-chan trx.Result[U] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[U](conf)
+
+	go func() {
+		defer close(out)
+
+		acc := seed
+		i := 0
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[U](err)
+
+					return
+				}
+
+				acc, err = accumulator(acc, value, i)
+				if err != nil {
+					out <- trx.Err[U](err)
+
+					return
+				}
+
+				i++
+			}
+		}
+
+		out <- trx.Ok(acc)
+	}()
+
+	return out
+}
+
+// DistinctCount emits, after each value of the source channel, the number of distinct values seen so far,
+// which is useful for monitoring the cardinality of a live stream. If an error is received from the source,
+// it is sent downstream wrapped in a trx.Result and counting stops.
+//
+// Every distinct value is kept in memory for the lifetime of the stream, so memory grows without bound with
+// the cardinality of the source. An approximate, constant-memory variant (for example HyperLogLog-based)
+// would suit very high-cardinality streams but is not provided.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel. Must be comparable.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[int] that emits the running distinct count after each value, or an error.
+//
+// Example usage:
+//
+//	out := DistinctCount(userIDs) // 1, 2, 2, 3, ...
+func DistinctCount[T comparable](source <-chan trx.Result[T], options ...Option) <-chan trx.Result[int] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[int](conf)
+
+	go func() {
+		defer close(out)
+
+		seen := make(map[T]struct{})
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[int](err)
+
+					return
+				}
+
+				seen[value] = struct{}{}
+				out <- trx.Ok(len(seen))
+			}
+		}
+	}()
+
+	return out
+}
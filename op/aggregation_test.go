@@ -0,0 +1,320 @@
+package op_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/foreveralonet/trx"
+	"github.com/foreveralonet/trx/op"
+)
+
+var _ = Describe("Aggregation Operations", func() {
+
+	Describe("FoldLeft and FoldRight", func() {
+		Context("when folding with a non-associative operation", func() {
+			It("should fold from the left", func() {
+				out := op.FoldLeft(op.Range(1, 3), 0, func(acc int, v int) int {
+					return acc - v
+				})
+
+				result := <-out
+				Expect(result.IsOk()).To(BeTrue())
+				Expect(result.Unwrap()).To(Equal(-6)) // ((0 - 1) - 2) - 3
+
+				_, ok := <-out
+				Expect(ok).To(BeFalse())
+			})
+
+			It("should fold from the right", func() {
+				out := op.FoldRight(op.Range(1, 3), 0, func(v int, acc int) int {
+					return v - acc
+				})
+
+				result := <-out
+				Expect(result.IsOk()).To(BeTrue())
+				Expect(result.Unwrap()).To(Equal(2)) // 1 - (2 - (3 - 0))
+
+				_, ok := <-out
+				Expect(ok).To(BeFalse())
+			})
+
+			It("should fold strings in a different order", func() {
+				left := op.FoldLeft(op.FormSlice([]string{"a", "b", "c"}), "", func(acc string, v string) string {
+					return acc + v
+				})
+				right := op.FoldRight(op.FormSlice([]string{"a", "b", "c"}), "", func(v string, acc string) string {
+					return acc + v
+				})
+
+				leftResult := <-left
+				rightResult := <-right
+				Expect(leftResult.Unwrap()).To(Equal("abc"))
+				Expect(rightResult.Unwrap()).To(Equal("cba"))
+			})
+		})
+
+		Context("when the source is empty", func() {
+			It("should emit the seed", func() {
+				left := op.FoldLeft(op.Range(0, 0), 42, func(acc int, v int) int { return acc + v })
+				right := op.FoldRight(op.Range(0, 0), 42, func(v int, acc int) int { return acc + v })
+
+				leftResult := <-left
+				rightResult := <-right
+				Expect(leftResult.Unwrap()).To(Equal(42))
+				Expect(rightResult.Unwrap()).To(Equal(42))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should abort both folds with the error", func() {
+				testError := errors.New("source error")
+				makeSource := func() <-chan trx.Result[int] {
+					source := make(chan trx.Result[int], 3)
+					source <- trx.Ok(1)
+					source <- trx.Err[int](testError)
+					source <- trx.Ok(3)
+					close(source)
+
+					return source
+				}
+
+				left := op.FoldLeft(makeSource(), 0, func(acc int, v int) int { return acc - v })
+				right := op.FoldRight(makeSource(), 0, func(v int, acc int) int { return v - acc })
+
+				for _, out := range []<-chan trx.Result[int]{left, right} {
+					results := make([]trx.Result[int], 0)
+					for result := range out {
+						results = append(results, result)
+					}
+
+					Expect(results).To(HaveLen(1))
+					Expect(results[0].Err()).To(Equal(testError))
+				}
+			})
+		})
+	})
+
+	Describe("CountByKey", func() {
+		Context("when counting words", func() {
+			It("should emit the frequency of each word", func() {
+				words := op.FormSlice(strings.Fields("the cat and The dog and the bird"))
+				out := op.CountByKey(words, strings.ToLower)
+
+				result := <-out
+				Expect(result.Unwrap()).To(Equal(map[string]int{
+					"the": 3, "cat": 1, "and": 2, "dog": 1, "bird": 1,
+				}))
+
+				_, ok := <-out
+				Expect(ok).To(BeFalse())
+			})
+
+			It("should emit an empty map for an empty source", func() {
+				out := op.CountByKey(op.Range(0, 0), func(v int) int { return v })
+
+				result := <-out
+				Expect(result.Unwrap()).To(BeEmpty())
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should abort with the error", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[string], 2)
+				source <- trx.Ok("a")
+				source <- trx.Err[string](testError)
+				close(source)
+
+				result := <-op.CountByKey(source, strings.ToLower)
+				Expect(result.Err()).To(Equal(testError))
+			})
+		})
+	})
+
+	Describe("RollingReduce", func() {
+		sum := func(acc int, v int) int { return acc + v }
+
+		Context("when computing a rolling sum", func() {
+			It("should drop old values as they fall out of the window", func() {
+				out := op.RollingReduce(op.FormSlice([]int{1, 2, 3, 4, 10, 0}), 3, 0, sum)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				// 1, 1+2, 1+2+3, 2+3+4, 3+4+10, 4+10+0
+				Expect(results).To(Equal([]int{1, 3, 6, 9, 17, 14}))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should emit the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(2)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.RollingReduce(source, 2, 0, sum) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(2))
+				Expect(results[0].Unwrap()).To(Equal(1))
+				Expect(results[1].Err()).To(Equal(testError))
+			})
+		})
+	})
+
+	Describe("Accumulate", func() {
+		Context("when accumulating a running total", func() {
+			It("should pair each input with the running accumulation", func() {
+				out := op.Accumulate(op.FormSlice([]int{5, -2, 10}), 100, func(acc int, v int) (int, error) {
+					return acc + v, nil
+				})
+
+				steps := make([]trx.Step[int, int], 0)
+				for result := range out {
+					steps = append(steps, result.Unwrap())
+				}
+
+				Expect(steps).To(Equal([]trx.Step[int, int]{
+					{State: 105, Input: 5},
+					{State: 103, Input: -2},
+					{State: 113, Input: 10},
+				}))
+			})
+		})
+
+		Context("when the step function fails", func() {
+			It("should emit the error and stop", func() {
+				overdrawn := errors.New("overdrawn")
+				out := op.Accumulate(op.FormSlice([]int{5, -10, 20}), 0, func(acc int, v int) (int, error) {
+					if acc+v < 0 {
+						return acc, overdrawn
+					}
+
+					return acc + v, nil
+				})
+
+				results := make([]trx.Result[trx.Step[int, int]], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(2))
+				Expect(results[0].Unwrap()).To(Equal(trx.Step[int, int]{State: 5, Input: 5}))
+				Expect(results[1].Err()).To(Equal(overdrawn))
+			})
+		})
+	})
+
+	Describe("Reduce", func() {
+		Context("when reducing a stream", func() {
+			It("should emit only the final value", func() {
+				out := op.Reduce(op.FormSlice([]string{"a", "b", "c"}), "", func(acc string, v string, i int) (string, error) {
+					return acc + strconv.Itoa(i) + v, nil
+				})
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"0a1b2c"}))
+			})
+		})
+
+		Context("when the source is empty", func() {
+			It("should emit the seed", func() {
+				out := op.Reduce(op.Empty[int](), 42, func(acc int, v int, _ int) (int, error) {
+					return acc + v, nil
+				})
+
+				result := <-out
+				Expect(result.Unwrap()).To(Equal(42))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the accumulator fails", func() {
+			It("should emit the error and stop", func() {
+				tooLarge := errors.New("too large")
+				calls := 0
+				out := op.Reduce(op.FormSlice([]int{1, 2, 30, 4}), 0, func(acc int, v int, _ int) (int, error) {
+					calls++
+					if v > 10 {
+						return acc, tooLarge
+					}
+
+					return acc + v, nil
+				})
+
+				result := <-out
+				Expect(result.Err()).To(Equal(tooLarge))
+				Eventually(out).Should(BeClosed())
+				Expect(calls).To(Equal(3))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should forward the error and stop", func() {
+				sourceError := errors.New("source error")
+
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](sourceError)
+				close(source)
+
+				out := op.Reduce(source, 0, func(acc int, v int, _ int) (int, error) {
+					return acc + v, nil
+				})
+
+				result := <-out
+				Expect(result.Err()).To(Equal(sourceError))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("DistinctCount", func() {
+		Context("when the source contains repeats", func() {
+			It("should emit the running number of distinct values", func() {
+				out := op.DistinctCount(op.FormSlice([]string{"a", "b", "a", "c", "b", "d"}))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{1, 2, 2, 3, 3, 4}))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should emit the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(2)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.DistinctCount(source) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(2))
+				Expect(results[1].Err()).To(Equal(testError))
+			})
+		})
+	})
+})
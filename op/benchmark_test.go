@@ -0,0 +1,49 @@
+package op_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/foreveralonet/trx"
+	"github.com/foreveralonet/trx/op"
+)
+
+// burstySource emits n values in bursts of burst values, pausing between bursts.
+func burstySource(n int, burst int, pause time.Duration) <-chan trx.Result[int] {
+	source := make(chan trx.Result[int])
+
+	go func() {
+		defer close(source)
+
+		for i := 0; i < n; i++ {
+			if i > 0 && i%burst == 0 {
+				time.Sleep(pause)
+			}
+			source <- trx.Ok(i)
+		}
+	}()
+
+	return source
+}
+
+func benchmarkMapBursty(b *testing.B, options ...op.Option) {
+	mapper := func(value int, _ int) (int, error) {
+		time.Sleep(50 * time.Microsecond) // Simulate work
+
+		return value, nil
+	}
+
+	for i := 0; i < b.N; i++ {
+		out := op.Map(burstySource(256, 32, time.Millisecond), mapper, options...)
+		for range out {
+		}
+	}
+}
+
+func BenchmarkMapBurstyWithoutSourceBuffer(b *testing.B) {
+	benchmarkMapBursty(b, op.WithPoolSize(4))
+}
+
+func BenchmarkMapBurstyWithSourceBuffer(b *testing.B) {
+	benchmarkMapBursty(b, op.WithPoolSize(4), op.WithSourceBuffer(64))
+}
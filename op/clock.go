@@ -0,0 +1,22 @@
+package op
+
+import "time"
+
+// Clock provides the current time and timers to time-based operators.
+// The default implementation uses the real wall clock; tests can supply a fake one with WithClock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
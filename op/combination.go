@@ -0,0 +1,942 @@
+package op
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/foreveralonet/trx"
+)
+
+// RepeatWhen emits all values of a source created by factory and, each time that source completes,
+// notifies the notifier through the completions channel. Whenever the channel returned by notifier emits
+// a value, a fresh source is created by calling factory again and its values are relayed. The output
+// channel is closed once the notifier completes.
+//
+// Every completion is delivered to the notifier, in order, even if the notifier emits before reading it;
+// completions that the notifier has not read yet are queued, so a notifier may also ignore them.
+//
+// If an error is received from a source or from the notifier, it is sent downstream wrapped in a
+// trx.Result and repetition stops.
+//
+// Type Parameters:
+//
+//	T - The type of values emitted by the sources.
+//
+// Parameters:
+//
+//	factory  - A function that creates a new source channel for each repetition.
+//	notifier - A function that receives a channel of completion signals and returns a channel whose
+//	           emissions trigger a new repetition.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values of every repetition or errors.
+//
+// Example usage:
+//
+//	out := RepeatWhen(func() <-chan trx.Result[Row] {
+//	    return query()
+//	}, func(completions <-chan struct{}) <-chan trx.Result[any] {
+//	    return Map(Interval(time.Minute), func(v int, _ int) (any, error) { return v, nil })
+//	})
+func RepeatWhen[T any](factory func() <-chan trx.Result[T], notifier func(completions <-chan struct{}) <-chan trx.Result[any], options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		// The relay hands every completion to the notifier in order, keeping count of those it has not read
+		// yet, so completions are never skipped and the operator never waits for the notifier to read them.
+		completions := make(chan struct{})
+		completed := make(chan struct{})
+		relayed := make(chan struct{})
+		go func() {
+			defer close(relayed)
+			defer close(completions)
+
+			pending := 0
+			for {
+				var deliver chan<- struct{}
+				if pending > 0 {
+					deliver = completions
+				}
+
+				select {
+				case _, ok := <-completed:
+					if !ok {
+						return
+					}
+
+					pending++
+				case deliver <- struct{}{}:
+					pending--
+				}
+			}
+		}()
+		defer func() {
+			close(completed)
+			<-relayed
+		}()
+
+		notifications := notifier(completions)
+
+		for {
+			source := factory()
+
+		RELAY:
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case v, ok := <-source:
+					if !ok {
+						break RELAY
+					}
+
+					value, err := v.Get()
+					if err != nil {
+						select {
+						case <-ctx.Done():
+						case out <- trx.Err[T](err):
+						}
+
+						return
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case out <- trx.Ok(value):
+					}
+				}
+			}
+
+			// Deliver the completion before waiting for the notifier.
+			select {
+			case <-ctx.Done():
+				return
+			case completed <- struct{}{}:
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case n, ok := <-notifications:
+				if !ok {
+					return
+				}
+
+				if err := n.Err(); err != nil {
+					select {
+					case <-ctx.Done():
+					case out <- trx.Err[T](err):
+					}
+
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// MergeMapped merges two channels of different types into a single channel of type B. Values from a are
+// adapted with fa before being emitted, while values from b are forwarded as they are. Values are emitted
+// in the order they arrive, and errors from either source are forwarded downstream. The output channel is
+// closed once both sources are closed.
+//
+// Type Parameters:
+//
+//	A - The type of values from the first source channel.
+//	B - The type of values from the second source channel and of the output.
+//
+// Parameters:
+//
+//	a  - A receive-only channel of trx.Result[A] to adapt and merge.
+//	fa - A function that adapts a value of type A to type B.
+//	b  - A receive-only channel of trx.Result[B] to merge.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[B] containing the values of both sources or errors.
+//
+// Example usage:
+//
+//	out := MergeMapped(heartbeats, func(tick int) Event {
+//	    return Event{Kind: "heartbeat"}
+//	}, events)
+func MergeMapped[A, B any](a <-chan trx.Result[A], fa func(A) B, b <-chan trx.Result[B], options ...Option) <-chan trx.Result[B] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[B](conf)
+
+	go func() {
+		defer close(out)
+
+		for a != nil || b != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-a:
+				if !ok {
+					a = nil
+
+					continue
+				}
+
+				adapted := trx.Map(v, func(value A) (B, error) {
+					return fa(value), nil
+				})
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- adapted:
+				}
+			case v, ok := <-b:
+				if !ok {
+					b = nil
+
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// MergePriority merges several source channels into a single channel, preferring earlier-listed sources
+// whenever more than one source has a value ready. Unlike a plain select, which picks among ready channels
+// at random, this lets a source such as a control channel take precedence over a data channel. Errors from
+// any source are forwarded downstream. The output channel is closed once all sources are closed. Because Go
+// does not allow parameters after a variadic one, use MergePriorityWith to pass options.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channels.
+//
+// Parameters:
+//
+//	sources - The receive-only channels of trx.Result[T] to merge, highest priority first.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values of all sources or errors.
+//
+// Example usage:
+//
+//	out := MergePriority(commands, data)
+func MergePriority[T any](sources ...<-chan trx.Result[T]) <-chan trx.Result[T] {
+	return MergePriorityWith(nil, sources...)
+}
+
+// MergePriorityWith behaves like MergePriority but accepts options, which must come before the sources.
+// If the context is cancelled, the channel is closed without emitting further values.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channels.
+//
+// Parameters:
+//
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//	sources - The receive-only channels of trx.Result[T] to merge, highest priority first.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values of all sources or errors.
+//
+// Example usage:
+//
+//	out := MergePriorityWith([]Option{WithContext(ctx)}, commands, data)
+func MergePriorityWith[T any](options []Option, sources ...<-chan trx.Result[T]) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		mergeInTurn(ctx, out, sources, func() int { return 0 }, func(int) {})
+	}()
+
+	return out
+}
+
+// MergeRoundRobin merges several source channels into a single channel, reading them in strict round-robin
+// order: whenever more than one source has a value ready, each of them gets a turn before any source gets a
+// second one. Unlike a plain select, which picks among ready channels at random, this guarantees balanced
+// interleaving, so a fast source cannot starve a slow one. A source that has nothing ready is skipped for the
+// current turn rather than waited for. Errors from any source are forwarded downstream. The output channel is
+// closed once all sources are closed. Because Go does not allow parameters after a variadic one, use
+// MergeRoundRobinWith to pass options.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channels.
+//
+// Parameters:
+//
+//	sources - The receive-only channels of trx.Result[T] to merge.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values of all sources or errors.
+//
+// Example usage:
+//
+//	out := MergeRoundRobin(tenantA, tenantB, tenantC)
+func MergeRoundRobin[T any](sources ...<-chan trx.Result[T]) <-chan trx.Result[T] {
+	return MergeRoundRobinWith(nil, sources...)
+}
+
+// MergeRoundRobinWith behaves like MergeRoundRobin but accepts options, which must come before the sources.
+// If the context is cancelled, the channel is closed without emitting further values.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channels.
+//
+// Parameters:
+//
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//	sources - The receive-only channels of trx.Result[T] to merge.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values of all sources or errors.
+//
+// Example usage:
+//
+//	out := MergeRoundRobinWith([]Option{WithContext(ctx)}, tenantA, tenantB, tenantC)
+func MergeRoundRobinWith[T any](options []Option, sources ...<-chan trx.Result[T]) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		next := 0 // The source whose turn it is
+		mergeInTurn(ctx, out, sources, func() int { return next }, func(i int) {
+			next = (i + 1) % len(sources)
+		})
+	}()
+
+	return out
+}
+
+// mergeInTurn relays the results of sources to out until all of them are closed or ctx is cancelled.
+// Whenever several sources are ready, it receives from the first ready one scanning from the index returned
+// by first; when none is ready, it waits for any of them. After each receive, including the one reporting
+// that a source is closed, turn is called with the index of that source.
+func mergeInTurn[T any](ctx context.Context, out chan<- trx.Result[T], sources []<-chan trx.Result[T], first func() int, turn func(i int)) {
+	open := append([]<-chan trx.Result[T](nil), sources...)
+
+	// The first case is the cancellation of the context, followed by one case per source.
+	cases := make([]reflect.SelectCase, len(open)+1)
+	cases[0] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	for i, source := range open {
+		cases[i+1] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(source)}
+	}
+
+	// poll receives from the first ready source, starting at first, and reports whether one was ready.
+	poll := func() (int, trx.Result[T], bool, bool) {
+		start := first()
+		for k := range open {
+			i := (start + k) % len(open)
+			if open[i] == nil {
+				continue
+			}
+
+			select {
+			case v, ok := <-open[i]:
+				return i, v, ok, true
+			default:
+			}
+		}
+
+		return 0, trx.Result[T]{}, false, false
+	}
+
+	for remaining := len(open); remaining > 0; {
+		if ctx.Err() != nil {
+			return
+		}
+
+		i, v, ok, ready := poll()
+		if !ready {
+			chosen, received, recvOK := reflect.Select(cases)
+			if chosen == 0 {
+				return
+			}
+
+			i, ok = chosen-1, recvOK
+			if ok {
+				v = received.Interface().(trx.Result[T])
+			}
+		}
+
+		turn(i)
+
+		if !ok {
+			open[i] = nil
+			cases[i+1].Chan = reflect.Value{}
+			remaining--
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case out <- v:
+		}
+	}
+}
+
+// Interleave merges several source channels following a fixed, weighted pattern: it takes counts[i] results
+// from sources[i], then moves on to the next source, and repeats, for example 2 from A, then 1 from B, then 2
+// from A again. This supports prioritized multiplexing with a predictable ratio. Unlike MergeRoundRobin, each
+// turn waits for its source, so a slow source delays the others. Errors count as results and are forwarded
+// downstream. A source that completes is skipped from then on, and the output channel is closed once all
+// sources are closed. Counts that are missing or less than 1 are treated as 1. Because Go does not allow
+// parameters after a variadic one, use InterleaveWith to pass options.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channels.
+//
+// Parameters:
+//
+//	counts  - The number of results taken from each source per turn, in the order of sources.
+//	sources - The receive-only channels of trx.Result[T] to interleave.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values of all sources or errors.
+//
+// Example usage:
+//
+//	out := Interleave([]int{3, 1}, premium, free) // Three premium jobs for each free one
+func Interleave[T any](counts []int, sources ...<-chan trx.Result[T]) <-chan trx.Result[T] {
+	return InterleaveWith(nil, counts, sources...)
+}
+
+// InterleaveWith behaves like Interleave but accepts options, which must come before the other arguments.
+// If the context is cancelled, the channel is closed without emitting further values.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channels.
+//
+// Parameters:
+//
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//	counts  - The number of results taken from each source per turn, in the order of sources.
+//	sources - The receive-only channels of trx.Result[T] to interleave.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values of all sources or errors.
+//
+// Example usage:
+//
+//	out := InterleaveWith([]Option{WithContext(ctx)}, []int{3, 1}, premium, free)
+func InterleaveWith[T any](options []Option, counts []int, sources ...<-chan trx.Result[T]) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		open := append([]<-chan trx.Result[T](nil), sources...)
+		remaining := len(open)
+
+		for i := 0; remaining > 0; i = (i + 1) % len(open) {
+			if open[i] == nil {
+				continue
+			}
+
+			count := 1
+			if i < len(counts) {
+				count = max(counts[i], 1)
+			}
+
+			for range count {
+				var v trx.Result[T]
+				var ok bool
+
+				select {
+				case <-ctx.Done():
+					return
+				case v, ok = <-open[i]:
+				}
+
+				if !ok {
+					open[i] = nil
+					remaining--
+
+					break
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// ZipWith pairs the values of two source channels by position and combines each pair into a single value.
+// The nth output is combine(a[n], b[n]). If either value of a pair is an error, or combine returns an error,
+// the error is sent downstream wrapped in a trx.Result in place of that pair. Both sources are read
+// concurrently, and the output channel is closed as soon as either source is closed, without waiting
+// for another value from the other one, since it could never be paired.
+//
+// Type Parameters:
+//
+//	A - The type of values from the first source channel.
+//	B - The type of values from the second source channel.
+//	C - The type of the combined values.
+//
+// Parameters:
+//
+//	a       - A receive-only channel of trx.Result[A] providing the first value of each pair.
+//	b       - A receive-only channel of trx.Result[B] providing the second value of each pair.
+//	combine - A function that combines a pair into a value of type C, possibly returning an error.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[C] containing the combined values or errors.
+//
+// Example usage:
+//
+//	out := ZipWith(prices, quantities, func(p float64, q int) (float64, error) {
+//	    return p * float64(q), nil
+//	})
+func ZipWith[A, B, C any](a <-chan trx.Result[A], b <-chan trx.Result[B], combine func(a A, b B) (C, error), options ...Option) <-chan trx.Result[C] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[C](conf)
+
+	go func() {
+		defer close(out)
+
+		var (
+			va trx.Result[A]
+			vb trx.Result[B]
+		)
+
+		// A source is not read again until its pending value has been paired, so a closed source
+		// always means there is no partner left for the other side.
+		pendingA, pendingB := a, b
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-pendingA:
+				if !ok {
+					return
+				}
+
+				va, pendingA = v, nil
+			case v, ok := <-pendingB:
+				if !ok {
+					return
+				}
+
+				vb, pendingB = v, nil
+			}
+
+			if pendingA != nil || pendingB != nil {
+				continue
+			}
+
+			pendingA, pendingB = a, b
+
+			var result trx.Result[C]
+			if err := va.Err(); err != nil {
+				result = trx.Err[C](err)
+			} else if err := vb.Err(); err != nil {
+				result = trx.Err[C](err)
+			} else if combined, err := combine(va.Unwrap(), vb.Unwrap()); err != nil {
+				result = trx.Err[C](err)
+			} else {
+				result = trx.Ok(combined)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- result:
+			}
+		}
+	}()
+
+	return out
+}
+
+// ConcatValue forwards every result from the source channel and, once the source completes normally,
+// calls onComplete to compute a final value lazily. The value is appended to the stream only if onComplete
+// returns true. This is useful for emitting a summary record after the data. onComplete is not called when
+// the context is cancelled.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channel.
+//
+// Parameters:
+//
+//	source     - A receive-only channel of trx.Result[T] representing the input stream.
+//	onComplete - A function that computes the terminal value and reports whether it should be emitted.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the source results followed by the optional terminal value.
+//
+// Example usage:
+//
+//	count := 0
+//	counted := MapOk(records, func(r Record) Record { count++; return r })
+//	out := ConcatValue(counted, func() (Record, bool) {
+//	    return Record{Summary: true, Count: count}, count > 0
+//	})
+func ConcatValue[T any](source <-chan trx.Result[T], onComplete func() (T, bool), options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					if value, emit := onComplete(); emit {
+						select {
+						case <-ctx.Done():
+							return
+						case out <- trx.Ok(value):
+						}
+					}
+
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// RepeatLast forwards every result from the source channel and, once the source completes, keeps re-emitting
+// its last successful value every interval, latching the final state, for example to hold the last known
+// reading of a sensor that went quiet. If the source emitted no value, the channel is closed right after the
+// source completes. Otherwise the output never completes on its own: cancel the context to stop it.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	every  - The duration between repetitions of the last value after the source completes.
+//	options
+//	    - WithBufferSize
+//	    - WithClock
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the source results followed by repetitions of the last value.
+//
+// Example usage:
+//
+//	out := RepeatLast(readings, time.Second, WithContext(ctx))
+func RepeatLast[T any](source <-chan trx.Result[T], every time.Duration, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		var last T
+
+		hasLast := false
+
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				if value, err := v.Get(); err == nil {
+					last = value
+					hasLast = true
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+
+		if !hasLast {
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-conf.clock.After(every):
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- trx.Ok(last):
+			}
+		}
+	}()
+
+	return out
+}
+
+// SwitchIfEmpty forwards every result from the source channel, but if the source completes without emitting
+// anything, it switches to the alternate channel and forwards its results instead. Unlike appending a single
+// default value, this falls back to a whole stream, such as a secondary data store. Errors count as
+// emissions, so a source that only emits an error does not trigger the fallback. The alternate channel is
+// not read at all when the source emits something.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channels.
+//
+// Parameters:
+//
+//	source    - A receive-only channel of trx.Result[T] representing the primary input stream.
+//	alternate - A receive-only channel of trx.Result[T] used when the source turns out to be empty.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the results of the source, or of alternate if the source is empty.
+//
+// Example usage:
+//
+//	out := SwitchIfEmpty(cacheHits, databaseRows)
+func SwitchIfEmpty[T any](source <-chan trx.Result[T], alternate <-chan trx.Result[T], options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		empty := true
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					if !empty || alternate == nil {
+						return
+					}
+
+					// Switch once: the completion of alternate ends the stream
+					source, alternate, empty = alternate, nil, false
+
+					continue
+				}
+
+				empty = false
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// OrderedMerge merges several individually sorted source channels into a single sorted channel, which is the
+// streaming merge step of an external sort (a k-way merge). It waits until every open source has a value
+// ready, emits the smallest one according to less, and then reads the next value from that source. Values
+// that compare equal are emitted in the order of their sources. Errors from any source are forwarded
+// downstream as soon as they are read. The output channel is closed once all sources are closed.
+//
+// If a source is not sorted, the output is not sorted either; no error is reported. Because Go does not
+// allow parameters after a variadic one, use OrderedMergeWith to pass options.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channels.
+//
+// Parameters:
+//
+//	less    - A function that reports whether a sorts before b.
+//	sources - The receive-only channels of trx.Result[T] to merge, each sorted according to less.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values of all sources in sorted order, or errors.
+//
+// Example usage:
+//
+//	out := OrderedMerge(func(a, b Record) bool { return a.Key < b.Key }, runs...)
+func OrderedMerge[T any](less func(a, b T) bool, sources ...<-chan trx.Result[T]) <-chan trx.Result[T] {
+	return OrderedMergeWith(nil, less, sources...)
+}
+
+// OrderedMergeWith behaves like OrderedMerge but accepts options, which must come before the other arguments.
+// If the context is cancelled, the channel is closed without emitting further values.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channels.
+//
+// Parameters:
+//
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//	less    - A function that reports whether a sorts before b.
+//	sources - The receive-only channels of trx.Result[T] to merge, each sorted according to less.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values of all sources in sorted order, or errors.
+//
+// Example usage:
+//
+//	out := OrderedMergeWith([]Option{WithContext(ctx)}, func(a, b Record) bool { return a.Key < b.Key }, runs...)
+func OrderedMergeWith[T any](options []Option, less func(a, b T) bool, sources ...<-chan trx.Result[T]) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		heads := make([]*T, len(sources))
+
+		// fill reads the next value of source i into its head, forwarding errors on the way.
+		// It leaves the head nil once the source is closed, and reports false if the context is cancelled.
+		fill := func(i int) bool {
+			for {
+				select {
+				case <-ctx.Done():
+					return false
+				case v, ok := <-sources[i]:
+					if !ok {
+						return true
+					}
+
+					value, err := v.Get()
+					if err == nil {
+						heads[i] = &value
+
+						return true
+					}
+
+					select {
+					case <-ctx.Done():
+						return false
+					case out <- trx.Err[T](err):
+					}
+				}
+			}
+		}
+
+		for i := range sources {
+			if !fill(i) {
+				return
+			}
+		}
+
+		for {
+			smallest := -1
+			for i, head := range heads {
+				if head != nil && (smallest < 0 || less(*head, *heads[smallest])) {
+					smallest = i
+				}
+			}
+
+			if smallest < 0 {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- trx.Ok(*heads[smallest]):
+			}
+
+			heads[smallest] = nil
+			if !fill(smallest) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,626 @@
+package op_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/foreveralonet/trx"
+	"github.com/foreveralonet/trx/op"
+	"github.com/foreveralonet/trx/trxtest"
+)
+
+var _ = Describe("Combination Operations", func() {
+
+	Describe("RepeatWhen", func() {
+		countedNotifier := func(times int) func(<-chan struct{}) <-chan trx.Result[any] {
+			return func(completions <-chan struct{}) <-chan trx.Result[any] {
+				notifications := make(chan trx.Result[any])
+
+				go func() {
+					defer close(notifications)
+
+					for i := 0; i < times; i++ {
+						<-completions
+						notifications <- trx.Ok[any](i)
+					}
+				}()
+
+				return notifications
+			}
+		}
+
+		Context("when the notifier emits after each completion", func() {
+			It("should resubscribe to a fresh source each time", func() {
+				subscriptions := 0
+				out := op.RepeatWhen(func() <-chan trx.Result[int] {
+					subscriptions++
+
+					return op.Range(0, 3)
+				}, countedNotifier(2))
+
+				results := make([]int, 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1, 2, 0, 1, 2, 0, 1, 2}))
+				Expect(subscriptions).To(Equal(3))
+			})
+		})
+
+		Context("when the notifier completes immediately", func() {
+			It("should emit the source only once", func() {
+				out := op.RepeatWhen(func() <-chan trx.Result[int] {
+					return op.Range(0, 2)
+				}, countedNotifier(0))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1}))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should propagate the error and stop repeating", func() {
+				testError := errors.New("source error")
+				out := op.RepeatWhen(func() <-chan trx.Result[int] {
+					source := make(chan trx.Result[int], 2)
+					source <- trx.Ok(1)
+					source <- trx.Err[int](testError)
+					close(source)
+
+					return source
+				}, countedNotifier(5))
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(2))
+				Expect(results[1].Err()).To(Equal(testError))
+			})
+		})
+
+		Context("when the notifier fires before reading the completion", func() {
+			It("should still deliver every completion", func() {
+				completed := 0
+
+				out := op.RepeatWhen(func() <-chan trx.Result[int] {
+					return op.Range(0, 2)
+				}, func(completions <-chan struct{}) <-chan trx.Result[any] {
+					notifications := make(chan trx.Result[any], 2)
+					notifications <- trx.Ok[any](0)
+					notifications <- trx.Ok[any](1)
+
+					go func() {
+						defer close(notifications)
+
+						for range 3 {
+							<-completions
+							completed++
+						}
+					}()
+
+					return notifications
+				})
+
+				results, err := trxtest.Collect(out, 5*time.Second)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(HaveLen(6))
+				Expect(completed).To(Equal(3))
+			})
+		})
+	})
+
+	Describe("MergeMapped", func() {
+		Context("when merging sources of different types", func() {
+			It("should adapt the first source and emit values from both", func() {
+				ints := op.Range(1, 3)
+				strs := op.FormSlice([]string{"a", "b"})
+
+				out := op.MergeMapped(ints, func(v int) string {
+					return strconv.Itoa(v)
+				}, strs)
+
+				results := make([]string, 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(ConsistOf("1", "2", "3", "a", "b"))
+			})
+		})
+
+		Context("when a source contains an error", func() {
+			It("should forward the error", func() {
+				testError := errors.New("source error")
+				ints := make(chan trx.Result[int], 1)
+				ints <- trx.Err[int](testError)
+				close(ints)
+
+				out := op.MergeMapped(ints, strconv.Itoa, op.FormSlice([]string{"a"}))
+
+				errs := make([]error, 0)
+				values := make([]string, 0)
+				for result := range out {
+					if result.IsErr() {
+						errs = append(errs, result.Err())
+
+						continue
+					}
+					values = append(values, result.Unwrap())
+				}
+
+				Expect(errs).To(Equal([]error{testError}))
+				Expect(values).To(Equal([]string{"a"}))
+			})
+		})
+	})
+
+	Describe("MergePriority", func() {
+		Context("when several sources have values ready", func() {
+			It("should prefer the earlier-listed source", func() {
+				high := make(chan trx.Result[string], 5)
+				low := make(chan trx.Result[string], 5)
+				for i := 0; i < 5; i++ {
+					high <- trx.Ok("high")
+					low <- trx.Ok("low")
+				}
+				close(high)
+				close(low)
+
+				out := op.MergePriority(high, low)
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{
+					"high", "high", "high", "high", "high",
+					"low", "low", "low", "low", "low",
+				}))
+			})
+		})
+
+		Context("when sources produce values over time", func() {
+			It("should emit every value and close when all sources close", func() {
+				out := op.MergePriority(op.Range(0, 3), op.Range(10, 3))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(ConsistOf(0, 1, 2, 10, 11, 12))
+			})
+		})
+
+		Context("when the context is cancelled while the consumer has stopped reading", func() {
+			It("should stop without leaking goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					out := op.MergePriorityWith([]op.Option{op.WithBufferSize(1), op.WithContext(ctx)},
+						op.Range(0, 100, op.WithContext(ctx)),
+						make(chan trx.Result[int]),
+					)
+
+					<-out
+					cancel()
+
+					Eventually(out).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("MergeRoundRobin", func() {
+		Context("when a fast and a slow source are merged", func() {
+			It("should alternate between sources while both have values", func() {
+				fast := make(chan trx.Result[string], 100)
+				slow := make(chan trx.Result[string], 10)
+				for i := 0; i < 100; i++ {
+					fast <- trx.Ok("fast")
+				}
+				for i := 0; i < 10; i++ {
+					slow <- trx.Ok("slow")
+				}
+				close(fast)
+				close(slow)
+
+				out := op.MergeRoundRobin(fast, slow)
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(HaveLen(110))
+
+				counts := map[string]int{}
+				for _, v := range results[:20] {
+					counts[v]++
+				}
+				Expect(counts).To(Equal(map[string]int{"fast": 10, "slow": 10}))
+			})
+		})
+
+		Context("when sources produce values over time", func() {
+			It("should emit every value and close when all sources close", func() {
+				out := op.MergeRoundRobin(op.Range(0, 3), op.Range(10, 3), op.Range(20, 3))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(ConsistOf(0, 1, 2, 10, 11, 12, 20, 21, 22))
+			})
+		})
+
+		Context("when the context is cancelled while the consumer has stopped reading", func() {
+			It("should stop without leaking goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					out := op.MergeRoundRobinWith([]op.Option{op.WithBufferSize(1), op.WithContext(ctx)},
+						op.Range(0, 100, op.WithContext(ctx)),
+						make(chan trx.Result[int]),
+					)
+
+					<-out
+					cancel()
+
+					Eventually(out).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Interleave", func() {
+		Context("when two sources are interleaved with counts [2, 1]", func() {
+			It("should take two from the first source for each one from the second", func() {
+				out := op.Interleave([]int{2, 1},
+					op.FormSlice([]string{"a1", "a2", "a3", "a4", "a5", "a6"}),
+					op.FormSlice([]string{"b1", "b2", "b3"}),
+				)
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"a1", "a2", "b1", "a3", "a4", "b2", "a5", "a6", "b3"}))
+			})
+		})
+
+		Context("when sources complete at different times", func() {
+			It("should skip exhausted sources", func() {
+				out := op.Interleave([]int{2, 1},
+					op.FormSlice([]string{"a1", "a2", "a3"}),
+					op.FormSlice([]string{"b1", "b2", "b3", "b4"}),
+				)
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"a1", "a2", "b1", "a3", "b2", "b3", "b4"}))
+			})
+		})
+
+		Context("when the context is cancelled", func() {
+			It("should stop waiting on a source without leaking goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					stalled := make(chan trx.Result[int])
+					out := op.InterleaveWith([]op.Option{op.WithContext(ctx)}, []int{1, 1}, op.Range(0, 100, op.WithContext(ctx)), stalled)
+
+					first := <-out
+					Expect(first.Unwrap()).To(Equal(0))
+					cancel() // Interleave is waiting on the stalled source
+
+					Eventually(out).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("ZipWith", func() {
+		sum := func(a int, b int) (int, error) { return a + b, nil }
+
+		Context("when combining two streams element-wise", func() {
+			It("should sum pairs by position", func() {
+				out := op.ZipWith(op.Range(1, 4), op.Range(10, 4), sum)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{11, 13, 15, 17}))
+			})
+
+			It("should complete when the shorter source closes", func() {
+				out := op.ZipWith(op.Range(1, 2), op.Range(10, 5), sum)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{11, 13}))
+			})
+
+			It("should complete without waiting on the other source when one closes first", func() {
+				a := make(chan trx.Result[int])
+				defer close(a)
+
+				b := make(chan trx.Result[int])
+				close(b)
+
+				out := op.ZipWith(a, b, sum)
+
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when a source or combine fails", func() {
+			It("should forward the errors in place of the pair", func() {
+				testError := errors.New("source error")
+				combineError := errors.New("combine error")
+
+				a := make(chan trx.Result[int], 3)
+				a <- trx.Ok(1)
+				a <- trx.Err[int](testError)
+				a <- trx.Ok(3)
+				close(a)
+
+				out := op.ZipWith(a, op.Range(10, 3), func(x int, y int) (int, error) {
+					if x == 3 {
+						return 0, combineError
+					}
+
+					return x + y, nil
+				})
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(3))
+				Expect(results[0].Unwrap()).To(Equal(11))
+				Expect(results[1].Err()).To(Equal(testError))
+				Expect(results[2].Err()).To(Equal(combineError))
+			})
+		})
+	})
+
+	Describe("ConcatValue", func() {
+		Context("when the source completes", func() {
+			It("should append the computed value when requested", func() {
+				total := 0
+				counted := op.MapOk(op.Range(1, 4), func(v int) int {
+					total += v
+
+					return v
+				})
+
+				out := op.ConcatValue(counted, func() (int, bool) {
+					return total, true
+				})
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{1, 2, 3, 4, 10}))
+			})
+
+			It("should not append anything when not requested", func() {
+				out := op.ConcatValue(op.Range(1, 2), func() (int, bool) {
+					return -1, false
+				})
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{1, 2}))
+			})
+		})
+	})
+
+	Describe("RepeatLast", func() {
+		Context("when the source completes with values", func() {
+			It("should repeat the last value at the interval after completion", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				out := op.RepeatLast(op.Range(1, 3), time.Second, op.WithClock(clock), op.WithContext(ctx))
+
+				for _, expected := range []int{1, 2, 3} {
+					result := <-out
+					Expect(result.Unwrap()).To(Equal(expected))
+				}
+
+				for i := 0; i < 3; i++ {
+					Eventually(clock.Waits).Should(HaveLen(i + 1))
+					Consistently(out, 20*time.Millisecond).ShouldNot(Receive())
+
+					clock.Advance(time.Second)
+
+					var result trx.Result[int]
+					Eventually(out).Should(Receive(&result))
+					Expect(result.Unwrap()).To(Equal(3))
+				}
+
+				Expect(clock.Waits()).To(HaveEach(time.Second))
+
+				cancel()
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source is empty", func() {
+			It("should emit nothing and close", func() {
+				out := op.RepeatLast(op.Empty[int](), time.Millisecond)
+
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("SwitchIfEmpty", func() {
+		Context("when the source is empty", func() {
+			It("should fall back to the alternate stream", func() {
+				out := op.SwitchIfEmpty(op.Empty[int](), op.Range(10, 3))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{10, 11, 12}))
+			})
+		})
+
+		Context("when the source emits values", func() {
+			It("should forward the source and not read the alternate", func() {
+				alternate := make(chan trx.Result[int], 1)
+				alternate <- trx.Ok(99)
+				close(alternate)
+
+				out := op.SwitchIfEmpty(op.Range(0, 2), alternate)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1}))
+				Expect(alternate).To(HaveLen(1))
+			})
+
+			It("should treat an error as an emission", func() {
+				testError := errors.New("source error")
+				out := op.SwitchIfEmpty(op.Throw[int](testError), op.Range(10, 3))
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].Err()).To(Equal(testError))
+			})
+		})
+	})
+
+	Describe("OrderedMerge", func() {
+		less := func(a, b int) bool { return a < b }
+
+		Context("when merging pre-sorted sources", func() {
+			It("should emit a globally sorted stream", func() {
+				out := op.OrderedMerge(less,
+					op.FormSlice([]int{1, 4, 7, 10}),
+					op.FormSlice([]int{2, 5, 8}),
+					op.FormSlice([]int{0, 3, 3, 6, 9, 11, 12}),
+				)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1, 2, 3, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}))
+			})
+
+			It("should handle empty sources", func() {
+				out := op.OrderedMerge(less, op.Empty[int](), op.FormSlice([]int{1, 2}), op.Empty[int]())
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{1, 2}))
+			})
+		})
+
+		Context("when a source contains an error", func() {
+			It("should forward the error and keep merging", func() {
+				testError := errors.New("source error")
+				a := make(chan trx.Result[int], 3)
+				a <- trx.Ok(1)
+				a <- trx.Err[int](testError)
+				a <- trx.Ok(3)
+				close(a)
+
+				out := op.OrderedMerge(less, a, op.FormSlice([]int{2, 4}))
+
+				values := make([]int, 0)
+				errs := make([]error, 0)
+				for result := range out {
+					if result.IsErr() {
+						errs = append(errs, result.Err())
+
+						continue
+					}
+					values = append(values, result.Unwrap())
+				}
+
+				Expect(values).To(Equal([]int{1, 2, 3, 4}))
+				Expect(errs).To(Equal([]error{testError}))
+			})
+		})
+
+		Context("when the context is cancelled while the consumer has stopped reading", func() {
+			It("should stop without leaking goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					testError := errors.New("source error")
+					a := make(chan trx.Result[int], 2)
+					a <- trx.Err[int](testError)
+					a <- trx.Ok(1)
+					close(a)
+
+					out := op.OrderedMergeWith([]op.Option{op.WithContext(ctx)}, less, a, op.Range(0, 100, op.WithContext(ctx)))
+
+					// Leave the error and every value unread.
+					time.Sleep(10 * time.Millisecond)
+					cancel()
+
+					Eventually(out).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+})
@@ -1,6 +1,9 @@
 package op
 
 import (
+	"context"
+	"iter"
+	"slices"
 	"time"
 
 	"github.com/foreveralonet/trx"
@@ -59,6 +62,7 @@ func Timer(d time.Duration, options ...Option) <-chan trx.Result[int] {
 //	options
 //	    - WithBufferSize
 //	    - WithContext
+//	    - WithCoalesce
 //
 // Returns:
 //
@@ -78,12 +82,113 @@ func Interval(d time.Duration, options ...Option) <-chan trx.Result[int] {
 		ticker := time.NewTicker(d)
 		defer ticker.Stop()
 
+		if conf.coalesce {
+			coalesceTicks(ctx, ticker, out)
+
+			return
+		}
+
 		for i := 0; ; i++ {
 			select {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				out <- trx.Ok(i)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- trx.Ok(i):
+			}
+		}
+	}()
+
+	return out
+}
+
+// IntervalWithStop behaves like Interval but also returns a stop function that ends the interval and
+// releases its ticker goroutine, giving explicit lifecycle control without constructing a context. After
+// stop is called, the channel is closed without emitting further values, even if nobody is reading it.
+// Calling stop more than once has no effect. If WithContext is given, cancelling that context also ends
+// the interval.
+//
+// Type Parameters:
+//
+//	None.
+//
+// Parameters:
+//
+//	d       - The duration between emissions.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//	    - WithCoalesce
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[int] that emits incrementing integers at each interval, and a
+//	function that stops it.
+//
+// Example usage:
+//
+//	ticks, stop := IntervalWithStop(time.Second)
+//	defer stop()
+//	for res := range Take(ticks, 5) {
+//	    // handle res
+//	}
+func IntervalWithStop(d time.Duration, options ...Option) (<-chan trx.Result[int], func()) {
+	ctx, stop := context.WithCancel(makeContext(parseOption(options...)))
+
+	return Interval(d, append(slices.Clip(options), WithContext(ctx))...), stop
+}
+
+// IntervalPrecise emits a trx.Result[int] at each interval specified by the duration d, incrementing the value
+// each time, like Interval. Instead of relying on a ticker, it schedules the nth emission at the absolute time
+// start + (n+1)*d, so delays caused by a slow consumer or scheduling jitter are compensated and do not
+// accumulate. If an emission is already late, it happens immediately.
+// If the context is cancelled, the channel is closed without emitting further values.
+//
+// Type Parameters:
+//
+//	None.
+//
+// Parameters:
+//
+//	d       - The duration between emissions.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//	    - WithClock
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[int] that emits incrementing integers at precise multiples of d.
+//
+// Example usage:
+//
+//	out := IntervalPrecise(1 * time.Second)
+func IntervalPrecise(d time.Duration, options ...Option) <-chan trx.Result[int] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[int](conf)
+
+	go func() {
+		defer close(out)
+
+		start := conf.clock.Now()
+		for i := 0; ; i++ {
+			next := start.Add(time.Duration(i+1) * d)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-conf.clock.After(next.Sub(conf.clock.Now())):
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- trx.Ok(i):
 			}
 		}
 	}()
@@ -91,6 +196,41 @@ func Interval(d time.Duration, options ...Option) <-chan trx.Result[int] {
 	return out
 }
 
+// coalesceTicks keeps reading the ticker while the consumer is busy, remembering only the latest tick
+// so that a slow consumer never receives a burst of stale ticks. With a buffered output, at most one
+// tick is ever queued: a newer tick replaces the queued one instead of piling up behind it.
+func coalesceTicks(ctx context.Context, ticker *time.Ticker, out chan trx.Result[int]) {
+	var send chan<- trx.Result[int] // nil while no tick is pending
+
+	i := 0
+	pending := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pending = i
+			i++
+
+			if cap(out) == 0 {
+				send = out
+
+				continue
+			}
+
+			// Only this goroutine sends, so once the stale tick is gone (read by the consumer or
+			// discarded here) the buffer has room and the send below cannot block.
+			select {
+			case <-out:
+			default:
+			}
+			out <- trx.Ok(pending)
+		case send <- trx.Ok(pending):
+			send = nil
+		}
+	}
+}
+
 // FormSlice emits each element of the provided slice source as a trx.Result[T] on the returned channel.
 // If the context is cancelled, the channel is closed without emitting further values.
 //
@@ -124,8 +264,141 @@ func FormSlice[T any](source []T, options ...Option) <-chan trx.Result[T] {
 			select {
 			case <-ctx.Done():
 				return
-			default:
-				out <- trx.Ok(v)
+			case out <- trx.Ok(v):
+			}
+		}
+	}()
+
+	return out
+}
+
+// Just emits each of the given values in order as a trx.Result[T] and then closes the channel. It is a
+// shorthand for starting a pipeline from a handful of literal values without building a slice first.
+// Calling it without values produces an empty, immediately closed channel. Because Go does not allow
+// parameters after a variadic one, use JustWith to pass options.
+//
+// Type Parameters:
+//
+//	T - The type of the values.
+//
+// Parameters:
+//
+//	values - The values to emit.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits each value in order.
+//
+// Example usage:
+//
+//	out := Just("a", "b", "c")
+func Just[T any](values ...T) <-chan trx.Result[T] {
+	return JustWith(nil, values...)
+}
+
+// JustWith behaves like Just but accepts options, which must come before the values.
+// If the context is cancelled, the channel is closed without emitting further values.
+//
+// Type Parameters:
+//
+//	T - The type of the values.
+//
+// Parameters:
+//
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//	values - The values to emit.
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits each value in order.
+//
+// Example usage:
+//
+//	out := JustWith([]Option{WithContext(ctx)}, 1, 2, 3)
+func JustWith[T any](options []Option, values ...T) <-chan trx.Result[T] {
+	return FormSlice(values, options...)
+}
+
+// FromSliceReverse emits each element of the provided slice source as a trx.Result[T], starting from the
+// last element. If the context is cancelled, the channel is closed without emitting further values.
+//
+// Type Parameters:
+//
+//	T - The type of elements in the input slice.
+//
+// Parameters:
+//
+//	source   - The slice of values to emit.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits each element of source in reverse order.
+//
+// Example usage:
+//
+//	out := FromSliceReverse([]int{1, 2, 3}) // Emits 3, 2, 1
+func FromSliceReverse[T any](source []T, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for i := len(source) - 1; i >= 0; i-- {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- trx.Ok(source[i]):
+			}
+		}
+	}()
+
+	return out
+}
+
+// FromSliceRepeat emits the elements of the provided slice source in order, cycling through the whole slice
+// the given number of times. If the context is cancelled, the channel is closed without emitting further values.
+//
+// Type Parameters:
+//
+//	T - The type of elements in the input slice.
+//
+// Parameters:
+//
+//	source   - The slice of values to emit.
+//	times    - How many times to emit the slice. Values less than 1 produce an empty stream.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits source times times in a row.
+//
+// Example usage:
+//
+//	out := FromSliceRepeat([]int{1, 2}, 2) // Emits 1, 2, 1, 2
+func FromSliceRepeat[T any](source []T, times int, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for range max(times, 0) {
+			for _, v := range source {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- trx.Ok(v):
+				}
 			}
 		}
 	}()
@@ -139,34 +412,83 @@ func FormSlice[T any](source []T, options ...Option) <-chan trx.Result[T] {
 // them as trx.Ok results to the output channel. If the context is cancelled or the source
 // channel is closed, the output channel is closed as well.
 //
+// With WithEmitCancellationError, a cancellation emits the cancellation cause before closing, while a
+// normal source close does not. After a cancellation the remaining values of the source are drained
+// in the background, so a producer blocked on sending to source is released once it closes source.
+//
 // Parameters:
 //
 //	source: The input channel of type T to read values from.
 //	options
 //			- WithBufferSize
 //			- WithContext
+//			- WithEmitCancellationError
 //
 // Returns:
 //   - A receive-only channel of trx.Result[T] containing the wrapped values from the source channel.
 func FormChannel[T any](source <-chan T, options ...Option) <-chan trx.Result[T] {
+	return FromChannelMap(source, func(v T) T { return v }, options...)
+}
+
+// FromChannelMap creates a new output channel of trx.Result[U] from the given source channel, applying the
+// pure transform fn to each value while wrapping it. It behaves like FormChannel followed by MapOk, but uses
+// a single goroutine, which makes it a cheap adapter for legacy channels. Cancellation is handled exactly
+// as in FormChannel.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channel.
+//	U - The type of output values after the transform.
+//
+// Parameters:
+//
+//	source - The input channel of type T to read values from.
+//	fn     - A function that transforms each value.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//	    - WithEmitCancellationError
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the transformed values from the source channel.
+//
+// Example usage:
+//
+//	out := FromChannelMap(legacyEvents, func(e LegacyEvent) Event { return e.Upgrade() })
+func FromChannelMap[T, U any](source <-chan T, fn func(value T) U, options ...Option) <-chan trx.Result[U] {
 	opts := append([]Option{WithBufferSize(cap(source))}, options...)
 
 	conf := parseOption(opts...)
 	ctx := makeContext(conf)
-	out := makeResultChannel[T](conf)
+	out := makeResultChannel[U](conf)
 
 	go func() {
 		defer close(out)
 
+		cancelled := func() {
+			go drain(source)
+			emitCancellation(conf, ctx, out)
+		}
+
 		for {
 			select {
 			case <-ctx.Done():
+				cancelled()
+
 				return
 			case v, ok := <-source:
 				if !ok {
 					return
 				}
-				out <- trx.Ok(v)
+
+				select {
+				case <-ctx.Done():
+					cancelled()
+
+					return
+				case out <- trx.Ok(fn(v)):
+				}
 			}
 		}
 	}()
@@ -174,6 +496,12 @@ func FormChannel[T any](source <-chan T, options ...Option) <-chan trx.Result[T]
 	return out
 }
 
+// drain discards the remaining values of source until it is closed.
+func drain[T any](source <-chan T) {
+	for range source {
+	}
+}
+
 // Range emits a sequence of trx.Result[int], starting from 'start' and producing 'count' consecutive values.
 // If the context is cancelled, the channel is closed without emitting further values.
 //
@@ -208,11 +536,323 @@ func Range(start int, count int, options ...Option) <-chan trx.Result[int] {
 			select {
 			case <-ctx.Done():
 				return
-			default:
-				out <- trx.Ok(i)
+			case out <- trx.Ok(i):
+			}
+		}
+	}()
+
+	return out
+}
+
+// Empty emits no values and closes the returned channel right away. It is useful for testing downstream
+// operators and for building conditional pipelines where one branch produces nothing.
+//
+// Type Parameters:
+//
+//	T - The element type of the returned channel.
+//
+// Parameters:
+//
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that is closed without emitting anything.
+//
+// Example usage:
+//
+//	out := Empty[int]()
+func Empty[T any](options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+	}()
+
+	return out
+}
+
+// Never emits no values and keeps the returned channel open until the context is cancelled, then closes it.
+// It is useful for testing timeout, merge and cancellation behavior. Without WithContext the channel is
+// never closed, and the goroutine behind it never exits.
+//
+// Type Parameters:
+//
+//	T - The element type of the returned channel.
+//
+// Parameters:
+//
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that is closed only when the context is cancelled.
+//
+// Example usage:
+//
+//	out := Never[int](WithContext(ctx))
+func Never[T any](options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		<-ctx.Done()
+	}()
+
+	return out
+}
+
+// Throw emits a single error result and then closes the returned channel. It is a convenient source for
+// exercising error-handling paths. If the context is cancelled before the error is sent, the channel is
+// closed without emitting anything.
+//
+// Type Parameters:
+//
+//	T - The element type of the returned channel.
+//
+// Parameters:
+//
+//	err      - The error to emit.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits err once.
+//
+// Example usage:
+//
+//	out := Map(Throw[int](errors.New("boom")), mapper)
+func Throw[T any](err error, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+		case out <- trx.Err[T](err):
+		}
+	}()
+
+	return out
+}
+
+// Defer builds its source lazily by calling factory from the operator's own goroutine and relays every result
+// of that source to the returned channel. Expensive construction, such as opening a file or starting a
+// database query, therefore happens off the caller's goroutine, and never happens at all if the context is
+// already cancelled. Go channels give no signal when a receiver starts waiting, so the factory runs as soon
+// as the goroutine starts rather than on the first receive. Each call to Defer calls factory once, so a
+// fresh source is built every time, which makes it a natural building block for resubscribing operators.
+// If the context is cancelled, the relay stops and the channel is closed.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channel.
+//
+// Parameters:
+//
+//	factory - A function that creates the source channel.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the results of the source built by factory.
+//
+// Example usage:
+//
+//	out := Defer(func() <-chan trx.Result[Row] {
+//	    return queryRows(db, query)
+//	})
+func Defer[T any](factory func() <-chan trx.Result[T], options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		source := factory()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
 			}
 		}
 	}()
 
 	return out
 }
+
+// FromFunc generates a stream by calling gen repeatedly with an index that starts at 0 and increments per
+// call. Each value gen returns is emitted as a trx.Ok result until gen returns false as its second value,
+// which completes the stream without emitting that call's value. If gen returns an error, it is emitted as
+// a trx.Err result and generation stops. This suits sources such as paginated APIs. If the context is
+// cancelled, generation stops between calls.
+//
+// Type Parameters:
+//
+//	T - The type of the generated values.
+//
+// Parameters:
+//
+//	gen - A function that produces the value for an index, whether the stream continues, and an optional error.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits the generated values.
+//
+// Example usage:
+//
+//	out := FromFunc(func(page int) ([]User, bool, error) {
+//	    users, err := client.ListUsers(page)
+//	    return users, len(users) > 0, err
+//	})
+func FromFunc[T any](gen func(index int) (T, bool, error), options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for i := 0; ctx.Err() == nil; i++ {
+			value, more, err := gen(i)
+			if err != nil {
+				select {
+				case <-ctx.Done():
+				case out <- trx.Err[T](err):
+				}
+
+				return
+			}
+
+			if !more {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- trx.Ok(value):
+			}
+		}
+	}()
+
+	return out
+}
+
+// FromIterator emits each value yielded by the iterator seq as a trx.Result[T] on the returned channel, which
+// lets range-over-func iterators feed a pipeline. The iterator is pulled one value at a time with iter.Pull,
+// so if the context is cancelled, iteration stops cleanly and the iterator's deferred cleanup runs.
+//
+// Type Parameters:
+//
+//	T - The type of values yielded by the iterator.
+//
+// Parameters:
+//
+//	seq      - The iterator to consume.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits each value of seq in order.
+//
+// Example usage:
+//
+//	out := FromIterator(maps.Keys(m))
+func FromIterator[T any](seq iter.Seq[T], options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		next, stop := iter.Pull(seq)
+		defer stop()
+
+		for ctx.Err() == nil {
+			v, ok := next()
+			if !ok {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- trx.Ok(v):
+			}
+		}
+	}()
+
+	return out
+}
+
+// FromIterator2 behaves like FromIterator for an iter.Seq2, emitting each yielded pair as a trx.Pair.
+//
+// Type Parameters:
+//
+//	K - The type of the first value yielded by the iterator.
+//	V - The type of the second value yielded by the iterator.
+//
+// Parameters:
+//
+//	seq      - The iterator to consume.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[trx.Pair[K, V]] that emits each pair of seq in order.
+//
+// Example usage:
+//
+//	out := FromIterator2(maps.All(m))
+func FromIterator2[K, V any](seq iter.Seq2[K, V], options ...Option) <-chan trx.Result[trx.Pair[K, V]] {
+	return FromIterator(func(yield func(trx.Pair[K, V]) bool) {
+		for k, v := range seq {
+			if !yield(trx.Pair[K, V]{Key: k, Value: v}) {
+				return
+			}
+		}
+	}, options...)
+}
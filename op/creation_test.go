@@ -1,12 +1,17 @@
 package op_test
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/foreveralonet/trx"
 	"github.com/foreveralonet/trx/op"
+	"github.com/foreveralonet/trx/trxtest"
 )
 
 var _ = Describe("Creation Operations", func() {
@@ -108,6 +113,151 @@ var _ = Describe("Creation Operations", func() {
 				Expect(secondElapsed).To(BeNumerically("~", expectedSecond, tolerance))
 			})
 		})
+
+		Context("when coalescing ticks for a slow consumer", func() {
+			It("should deliver the latest tick instead of a burst of stale ones", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				interval := 10 * time.Millisecond
+				out := op.Interval(interval, op.WithCoalesce(), op.WithContext(ctx))
+
+				first := <-out
+				Expect(first.Unwrap()).To(Equal(0))
+
+				time.Sleep(6 * interval) // Fall behind by several ticks
+
+				latest := <-out
+				Expect(latest.Unwrap()).To(BeNumerically(">=", 4))
+
+				// Stale ticks are not queued up behind the latest one
+				next := <-out
+				Expect(next.Unwrap()).To(BeNumerically(">", latest.Unwrap()))
+			})
+
+			It("should keep at most one pending tick when the output is buffered", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				interval := 10 * time.Millisecond
+				out := op.Interval(interval, op.WithCoalesce(), op.WithBufferSize(5), op.WithContext(ctx))
+
+				time.Sleep(10 * interval) // Slow reader: let many ticks pass unread
+
+				Expect(len(out)).To(BeNumerically("<=", 1))
+
+				latest := <-out
+				Expect(latest.Unwrap()).To(BeNumerically(">=", 5))
+
+				next := <-out
+				Expect(next.Unwrap()).To(BeNumerically(">", latest.Unwrap()))
+			})
+		})
+	})
+
+	Describe("IntervalWithStop", func() {
+		Context("when stop is called", func() {
+			It("should close the channel and release the ticker goroutine", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ticks, stop := op.IntervalWithStop(5 * time.Millisecond)
+
+					var result trx.Result[int]
+					Eventually(ticks).Should(Receive(&result))
+					Expect(result.Unwrap()).To(Equal(0))
+
+					stop()
+					stop()
+
+					Eventually(ticks).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should not leak when nobody reads the channel", func() {
+				err := trxtest.AssertNoLeak(func() {
+					_, stop := op.IntervalWithStop(time.Millisecond)
+
+					time.Sleep(20 * time.Millisecond)
+					stop()
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the parent context is cancelled", func() {
+			It("should close the channel", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				ticks, stop := op.IntervalWithStop(5*time.Millisecond, op.WithContext(ctx))
+				defer stop()
+
+				cancel()
+
+				Eventually(ticks).Should(BeClosed())
+			})
+		})
+
+		Context("when the options slice has spare capacity", func() {
+			It("should not write into the caller's slice", func() {
+				options := make([]op.Option, 1, 4)
+				options[0] = op.WithBufferSize(1)
+
+				_, stop := op.IntervalWithStop(time.Millisecond, options...)
+				stop()
+
+				Expect(options[:2][1]).To(BeNil())
+			})
+		})
+	})
+
+	Describe("IntervalPrecise", func() {
+		Context("when the consumer adds processing delay", func() {
+			It("should keep the nth emission at start + n*period", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				period := 30 * time.Millisecond
+				processing := 20 * time.Millisecond
+				start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+				clock := newFakeClock(start)
+				out := op.IntervalPrecise(period, op.WithContext(ctx), op.WithClock(clock))
+
+				for n := 1; n <= 5; n++ {
+					Eventually(clock.Waits).Should(HaveLen(n))
+					waits := clock.Waits()
+					clock.Advance(waits[n-1])
+					Expect(clock.Now()).To(Equal(start.Add(time.Duration(n) * period)))
+
+					clock.Advance(processing) // Simulate processing before the value is read
+
+					result := <-out
+					Expect(result.Unwrap()).To(Equal(n - 1))
+				}
+
+				// Every wait after the first is shortened by the processing delay
+				Expect(clock.Waits()[:5]).To(Equal([]time.Duration{period, period - processing, period - processing, period - processing, period - processing}))
+			})
+		})
+
+		Context("when using a fake clock", func() {
+			It("should schedule each emission at an absolute time", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				out := op.IntervalPrecise(time.Second, op.WithContext(ctx), op.WithClock(clock))
+
+				Eventually(clock.Waits).Should(Equal([]time.Duration{time.Second}))
+				clock.Advance(1300 * time.Millisecond) // Fire late
+
+				result := <-out
+				Expect(result.Unwrap()).To(Equal(0))
+
+				// The next wait is shortened to get back on schedule
+				Eventually(clock.Waits).Should(Equal([]time.Duration{time.Second, 700 * time.Millisecond}))
+			})
+		})
 	})
 
 	Describe("FormSlice", func() {
@@ -158,6 +308,106 @@ var _ = Describe("Creation Operations", func() {
 		})
 	})
 
+	Describe("Just", func() {
+		Context("when emitting literal values", func() {
+			It("should emit each value in order and close", func() {
+				results := make([]int, 0)
+				for result := range op.Just(3, 1, 2) {
+					Expect(result.IsOk()).To(BeTrue())
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{3, 1, 2}))
+			})
+
+			It("should produce an immediately closed channel without values", func() {
+				out := op.Just[int]()
+
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should work with pointer and interface element types", func() {
+				a, b := 1, 2
+				pointers := make([]*int, 0)
+				for result := range op.Just(&a, nil, &b) {
+					pointers = append(pointers, result.Unwrap())
+				}
+				Expect(pointers).To(Equal([]*int{&a, nil, &b}))
+
+				values := make([]any, 0)
+				for result := range op.Just[any]("x", 1, nil) {
+					values = append(values, result.Unwrap())
+				}
+				Expect(values).To(Equal([]any{"x", 1, nil}))
+			})
+		})
+
+		Context("when passing options with JustWith", func() {
+			It("should honor the buffer size", func() {
+				out := op.JustWith([]op.Option{op.WithBufferSize(3)}, 1, 2, 3)
+
+				Eventually(func() int { return len(out) }).Should(Equal(3))
+				Expect(cap(out)).To(Equal(3))
+			})
+
+			It("should stop emitting when the context is cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				out := op.JustWith([]op.Option{op.WithContext(ctx)}, 1, 2, 3)
+
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("FromSliceReverse", func() {
+		Context("when converting a slice to a channel", func() {
+			It("should emit all slice elements in reverse order", func() {
+				out := op.FromSliceReverse([]string{"a", "b", "c"})
+
+				results := make([]string, 0, 3)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"c", "b", "a"}))
+			})
+
+			It("should stop emitting when the context is cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				out := op.FromSliceReverse([]int{1, 2, 3}, op.WithContext(ctx))
+
+				first := <-out
+				Expect(first.Unwrap()).To(Equal(3))
+
+				cancel()
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("FromSliceRepeat", func() {
+		Context("when repeating a slice", func() {
+			It("should cycle through the slice the given number of times", func() {
+				out := op.FromSliceRepeat([]int{1, 2, 3}, 2)
+
+				results := make([]int, 0, 6)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{1, 2, 3, 1, 2, 3}))
+			})
+
+			It("should emit nothing when times is zero", func() {
+				out := op.FromSliceRepeat([]int{1, 2, 3}, 0)
+
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
 	Describe("FormChannel", func() {
 		Context("when converting a channel to a Result channel", func() {
 			It("should emit all channel values as Ok results", func() {
@@ -221,6 +471,153 @@ var _ = Describe("Creation Operations", func() {
 				Expect(count).To(Equal(0))
 			})
 		})
+
+		Context("when distinguishing source close from cancellation", func() {
+			It("should not emit an error when the source closes", func() {
+				input := make(chan int, 1)
+				input <- 1
+				close(input)
+
+				out := op.FormChannel(input, op.WithEmitCancellationError())
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].IsOk()).To(BeTrue())
+			})
+
+			It("should emit the context error when cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				input := make(chan int)
+
+				out := op.FormChannel(input, op.WithContext(ctx), op.WithEmitCancellationError())
+
+				input <- 1
+				first := <-out
+				Expect(first.Unwrap()).To(Equal(1))
+
+				cancel()
+
+				last := <-out
+				Expect(last.Err()).To(MatchError(context.Canceled))
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should release a producer blocked on the source after cancellation", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				input := make(chan int)
+				producerDone := make(chan struct{})
+
+				out := op.FormChannel(input, op.WithContext(ctx))
+				cancel()
+				Eventually(out).Should(BeClosed())
+
+				go func() {
+					defer close(producerDone)
+					defer close(input)
+
+					for i := 0; i < 3; i++ {
+						input <- i
+					}
+				}()
+
+				Eventually(producerDone).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("FromChannelMap", func() {
+		Context("when wrapping and transforming a raw channel", func() {
+			It("should behave like FormChannel followed by MapOk", func() {
+				newInput := func() <-chan int {
+					input := make(chan int, 5)
+					for i := 1; i <= 5; i++ {
+						input <- i
+					}
+					close(input)
+
+					return input
+				}
+				square := func(v int) int { return v * v }
+
+				expected := make([]int, 0)
+				for result := range op.MapOk(op.FormChannel(newInput()), square, op.WithSerialize()) {
+					expected = append(expected, result.Unwrap())
+				}
+
+				results := make([]int, 0)
+				for result := range op.FromChannelMap(newInput(), square) {
+					Expect(result.IsOk()).To(BeTrue())
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal(expected))
+				Expect(results).To(Equal([]int{1, 4, 9, 16, 25}))
+			})
+		})
+
+		Context("when the context is cancelled", func() {
+			It("should not block when the consumer stopped reading before cancelling", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancelCause(context.Background())
+
+					input := make(chan int)
+					go func() {
+						defer close(input)
+
+						for i := 0; ; i++ {
+							select {
+							case <-ctx.Done():
+								return
+							case input <- i:
+							}
+						}
+					}()
+
+					out := op.FromChannelMap(input, func(v int) int { return v }, op.WithContext(ctx), op.WithEmitCancellationError())
+
+					count := 0
+					for range out {
+						count++
+						if count == 5 {
+							break
+						}
+					}
+
+					cancel(errors.New("shutting down"))
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should emit the context error with WithEmitCancellationError", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				input := make(chan int)
+
+				out := op.FromChannelMap(input, func(v int) string { return "value" }, op.WithContext(ctx), op.WithEmitCancellationError())
+				cancel()
+
+				result := <-out
+				Expect(result.Err()).To(MatchError(context.Canceled))
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should emit the cancellation cause when one is given", func() {
+				cause := errors.New("shutting down")
+				ctx, cancel := context.WithCancelCause(context.Background())
+				input := make(chan int)
+
+				out := op.FromChannelMap(input, func(v int) string { return "value" }, op.WithContext(ctx), op.WithEmitCancellationError())
+				cancel(cause)
+
+				result := <-out
+				Expect(result.Err()).To(Equal(cause))
+				Eventually(out).Should(BeClosed())
+			})
+		})
 	})
 
 	Describe("Range", func() {
@@ -291,6 +688,236 @@ var _ = Describe("Creation Operations", func() {
 		})
 	})
 
+	Describe("Empty", func() {
+		Context("when creating an empty source", func() {
+			It("should close without emitting anything", func() {
+				out := op.Empty[int](op.WithBufferSize(4))
+
+				count := 0
+				for range out {
+					count++
+				}
+
+				Expect(count).To(Equal(0))
+				Expect(cap(out)).To(Equal(4))
+			})
+		})
+	})
+
+	Describe("Never", func() {
+		Context("when creating a source that never emits", func() {
+			It("should stay open until the context is cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				out := op.Never[int](op.WithContext(ctx))
+
+				Consistently(out, 50*time.Millisecond).ShouldNot(Receive())
+				Expect(out).NotTo(BeClosed())
+
+				cancel()
+
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("Throw", func() {
+		Context("when creating an error source", func() {
+			It("should emit the error once and close", func() {
+				testError := errors.New("test error")
+				out := op.Throw[int](testError)
+
+				result := <-out
+				Expect(result.IsErr()).To(BeTrue())
+				Expect(result.Err()).To(Equal(testError))
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should close without emitting when already cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				out := op.Throw[int](errors.New("test error"), op.WithContext(ctx), op.WithBufferSize(1))
+
+				count := 0
+				for range out {
+					count++
+				}
+
+				Expect(count).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Defer", func() {
+		Context("when the factory builds a source", func() {
+			It("should relay every result of the built source", func() {
+				out := op.Defer(func() <-chan trx.Result[int] {
+					return op.Range(0, 3)
+				})
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1, 2}))
+			})
+
+			It("should build a fresh source for each call", func() {
+				calls := 0
+				factory := func() <-chan trx.Result[int] {
+					calls++
+
+					return op.Just(calls)
+				}
+
+				first := <-op.Defer(factory)
+				second := <-op.Defer(factory)
+
+				Expect(first.Unwrap()).To(Equal(1))
+				Expect(second.Unwrap()).To(Equal(2))
+			})
+		})
+
+		Context("when the context is cancelled", func() {
+			It("should not call the factory if cancelled before starting", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				called := false
+				out := op.Defer(func() <-chan trx.Result[int] {
+					called = true
+
+					return op.Range(0, 3)
+				}, op.WithContext(ctx))
+
+				Eventually(out).Should(BeClosed())
+				Expect(called).To(BeFalse())
+			})
+
+			It("should stop relaying the source", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				out := op.Defer(func() <-chan trx.Result[int] {
+					return op.Interval(5*time.Millisecond, op.WithContext(ctx))
+				}, op.WithContext(ctx))
+
+				first := <-out
+				Expect(first.Unwrap()).To(Equal(0))
+
+				cancel()
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("FromFunc", func() {
+		Context("when generating values", func() {
+			It("should emit values until the generator reports completion", func() {
+				out := op.FromFunc(func(index int) (int, bool, error) {
+					return index * index, index < 4, nil
+				})
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1, 4, 9}))
+			})
+
+			It("should emit an error and stop", func() {
+				testError := errors.New("page error")
+				calls := 0
+				out := op.FromFunc(func(index int) (int, bool, error) {
+					calls++
+					if index == 2 {
+						return 0, true, testError
+					}
+
+					return index, true, nil
+				})
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(3))
+				Expect(results[2].Err()).To(Equal(testError))
+				Expect(calls).To(Equal(3))
+			})
+		})
+
+		Context("when the context is cancelled", func() {
+			It("should halt generation between calls", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				out := op.FromFunc(func(index int) (int, bool, error) {
+					return index, true, nil
+				}, op.WithContext(ctx))
+
+				first := <-out
+				Expect(first.Unwrap()).To(Equal(0))
+
+				cancel()
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("FromIterator", func() {
+		Context("when consuming an iter.Seq", func() {
+			It("should emit every value in order", func() {
+				out := op.FromIterator(slices.Values([]string{"a", "b", "c"}))
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"a", "b", "c"}))
+			})
+
+			It("should stop iterating and run cleanup when the context is cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cleanedUp := make(chan struct{})
+
+				naturals := func(yield func(int) bool) {
+					defer close(cleanedUp)
+
+					for i := 0; ; i++ {
+						if !yield(i) {
+							return
+						}
+					}
+				}
+
+				out := op.FromIterator(naturals, op.WithContext(ctx))
+				first := <-out
+				Expect(first.Unwrap()).To(Equal(0))
+
+				cancel()
+				Eventually(out).Should(BeClosed())
+				Eventually(cleanedUp).Should(BeClosed())
+			})
+		})
+
+		Context("when consuming an iter.Seq2", func() {
+			It("should emit every pair in order", func() {
+				out := op.FromIterator2(slices.All([]string{"a", "b"}))
+
+				results := make([]trx.Pair[int, string], 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]trx.Pair[int, string]{
+					{Key: 0, Value: "a"},
+					{Key: 1, Value: "b"},
+				}))
+			})
+		})
+	})
+
 	Describe("Integration with options", func() {
 		Context("when using WithBufferSize option", func() {
 			It("should create buffered channels", func() {
@@ -306,5 +933,27 @@ var _ = Describe("Creation Operations", func() {
 				Expect(count).To(Equal(3))
 			})
 		})
+
+		Context("when using WithContext option", func() {
+			It("should stop emitting when the context is cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				out := op.Range(0, 1000000, op.WithContext(ctx))
+
+				for i := 0; i < 3; i++ {
+					result := <-out
+					Expect(result.Unwrap()).To(Equal(i))
+				}
+
+				cancel()
+
+				count := 0
+				for range out {
+					count++
+				}
+
+				// At most one send can already be in flight when the cancellation is observed
+				Expect(count).To(BeNumerically("<=", 1))
+			})
+		})
 	})
 })
@@ -0,0 +1,160 @@
+package op
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/foreveralonet/trx"
+)
+
+// emitter sends results to an operator's output channel and applies the emission policies
+// configured through options, such as WithLimit. It is safe for concurrent use by pool workers.
+type emitter[T any] struct {
+	ctx    context.Context
+	out    chan<- trx.Result[T]
+	limit  int  // Maximum number of emissions (0 = unlimited)
+	drop   bool // Drop results when the output channel is full
+	onDrop func()
+	prefix string // Stage name prepended to errors (empty = unchanged)
+
+	mu       sync.Mutex
+	count    int
+	errored  bool
+	stopped  bool
+	stopPos  int // Position of the earliest item that called stop
+	finished chan struct{}
+	once     sync.Once
+}
+
+func newEmitter[T any](c *config, out chan<- trx.Result[T]) *emitter[T] {
+	prefix := ""
+	if c.wrapErrors {
+		prefix = c.name
+	}
+
+	return &emitter[T]{
+		ctx:      makeContext(c),
+		out:      out,
+		limit:    c.limit,
+		drop:     c.dropOnFull,
+		onDrop:   c.onDrop,
+		prefix:   prefix,
+		finished: make(chan struct{}),
+	}
+}
+
+// emit sends r downstream unless the emission limit has already been reached or stop was called.
+func (e *emitter[T]) emit(r trx.Result[T]) {
+	e.emitAt(math.MaxInt, r)
+}
+
+// emitAt behaves like emit for the result of the item at the given position in the source: once stop was
+// called, results of items that came before the stopping one are still sent, even if they finish later.
+func (e *emitter[T]) emitAt(position int, r trx.Result[T]) {
+	e.mu.Lock()
+	if e.stopped && position > e.stopPos {
+		e.mu.Unlock()
+
+		return
+	}
+
+	if r.IsErr() {
+		e.errored = true
+
+		if e.prefix != "" {
+			r = trx.Err[T](fmt.Errorf("%s: %w", e.prefix, r.Err()))
+		}
+	}
+
+	if e.limit <= 0 {
+		e.mu.Unlock()
+		e.send(r)
+
+		return
+	}
+
+	if e.count >= e.limit {
+		e.mu.Unlock()
+
+		return
+	}
+	e.count++
+	reached := e.count == e.limit
+	e.mu.Unlock()
+
+	if !e.send(r) {
+		// The result was dropped, give the slot back.
+		e.mu.Lock()
+		e.count--
+		e.mu.Unlock()
+
+		return
+	}
+
+	if reached {
+		e.once.Do(func() { close(e.finished) })
+	}
+}
+
+// hasErrored reports whether an error result has been emitted.
+func (e *emitter[T]) hasErrored() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.errored
+}
+
+// stop suppresses the emissions of the item at the given position and of every item after it, and signals
+// the operator to finish.
+func (e *emitter[T]) stop(position int) {
+	e.mu.Lock()
+	if !e.stopped || position < e.stopPos {
+		e.stopPos = position
+	}
+	e.stopped = true
+	e.mu.Unlock()
+
+	e.once.Do(func() { close(e.finished) })
+}
+
+// send writes r to the output channel and reports whether it was delivered. A blocked send gives up
+// when the context is cancelled, so a consumer that abandons the stream does not strand the workers.
+// In drop mode only successful values are dropped: errors are always delivered.
+func (e *emitter[T]) send(r trx.Result[T]) bool {
+	if !e.drop || r.IsErr() {
+		select {
+		case <-e.ctx.Done():
+			return false
+		case e.out <- r:
+			return true
+		}
+	}
+
+	select {
+	case e.out <- r:
+		return true
+	default:
+		if e.onDrop != nil {
+			e.onDrop()
+		}
+
+		return false
+	}
+}
+
+// done returns a channel that is closed once the emission limit has been reached or stop was called.
+func (e *emitter[T]) done() <-chan struct{} {
+	return e.finished
+}
+
+// isDone reports whether the emission limit has been reached or stop was called.
+func (e *emitter[T]) isDone() bool {
+	select {
+	case <-e.finished:
+		return true
+	default:
+		return false
+	}
+}
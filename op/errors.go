@@ -0,0 +1,15 @@
+package op
+
+import "errors"
+
+// ErrStop can be returned by a Map mapper to stop the whole stream early. Results of the items read before
+// it are still emitted, even when a concurrent pool finishes them later, while results of the items read
+// after it are dropped. The output channel is then closed normally: ErrStop itself is never emitted and the
+// source is no longer read.
+var ErrStop = errors.New("op: stop")
+
+// ErrNoElements is emitted when an operator expects at least one value from a channel that completes empty.
+var ErrNoElements = errors.New("op: no elements in sequence")
+
+// ErrIndexOutOfRange is emitted when an operator is asked for a position that the source does not reach.
+var ErrIndexOutOfRange = errors.New("op: index out of range")
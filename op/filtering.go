@@ -1,10 +1,18 @@
 package op
 
-import "github.com/foreveralonet/trx"
+import (
+	"container/list"
+	"math"
+	"time"
+
+	"github.com/foreveralonet/trx"
+)
 
 // Filter emits only those values from the source channel for which the predicate function returns true.
 // The predicate receives each value and its index, and may return an error. If an error occurs during
 // filtering or when retrieving the value from the source, the error is sent downstream wrapped in a trx.Result.
+// The index counts every result read from the source, including errors; WithContiguousIndex makes it
+// count only the values passed to the predicate.
 //
 // The function supports optional configuration via Option parameters, such as context control and concurrency
 // settings. Filtering operations are performed concurrently using a worker pool, and the output channel is
@@ -22,6 +30,18 @@ import "github.com/foreveralonet/trx"
 //	    - WithBufferSize
 //	    - WithPoolSize
 //	    - WithSerialize
+//	    - WithLimit
+//	    - WithDropOnBackpressure
+//	    - WithOnDrop
+//	    - WithPool
+//	    - WithOrderedConcurrency
+//	    - WithSourceBuffer
+//	    - WithContiguousIndex
+//	    - WithName
+//	    - WithWrapErrors
+//	    - WithOnStart
+//	    - WithOnStop
+//	    - WithEmitCancellationError
 //	    - WithContext
 //
 // Returns:
@@ -38,16 +58,26 @@ func Filter[T any](source <-chan trx.Result[T], predicate func(value T, index in
 	ctx := makeContext(conf)
 	out := makeResultChannel[T](conf)
 	pool := makePool(conf)
+	emitter := newEmitter(conf, out)
+	source = makeSourceBuffer(conf, ctx, emitter.done(), source)
 
 	go func() {
+		reason := StopCompleted
+		defer func() { conf.stopped(reason) }()
 		defer close(out)
 
+		conf.started()
+
 		i := 0
 	LOOP:
-		for {
+		for !emitter.isDone() {
 			select {
 			case <-ctx.Done():
-				return
+				reason = StopCancelled
+
+				break LOOP
+			case <-emitter.done():
+				break LOOP
 			case v, ok := <-source:
 				if !ok {
 					break LOOP
@@ -60,31 +90,219 @@ func Filter[T any](source <-chan trx.Result[T], predicate func(value T, index in
 					value, err := result.Get()
 					if err != nil {
 						return func() {
-							out <- trx.Err[T](err)
+							emitter.emit(trx.Err[T](err))
 						}
 					}
 
 					ok, err := predicate(value, index)
 					if err != nil {
 						return func() {
-							out <- trx.Err[T](err)
+							emitter.emit(trx.Err[T](err))
 						}
 					}
 
 					if ok {
 						return func() {
-							out <- trx.Ok(value)
+							emitter.emit(trx.Ok(value))
 						}
 					}
 
 					return func() {}
 				})
 
-				i++
+				if !conf.contiguous || result.IsOk() {
+					i++
+				}
 			}
 		}
 
+		// Workers blocked on emission are released by the cancellation, so waiting is safe.
 		pool.wait()
+
+		if reason == StopCancelled {
+			emitCancellation(conf, ctx, out)
+		}
+
+		if reason != StopCancelled && emitter.hasErrored() {
+			reason = StopErrored
+		}
+	}()
+
+	return out
+}
+
+// FilterOk emits only those successful values from the source channel for which the predicate returns true.
+// It is a convenience wrapper around Filter for predicates that never fail and do not need the index.
+// Errors received from the source are still sent downstream wrapped in a trx.Result.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	pred   - A function that determines if a value should be included.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithLimit
+//	    - WithDropOnBackpressure
+//	    - WithOnDrop
+//	    - WithSourceBuffer
+//	    - WithOnStart
+//	    - WithOnStop
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the filtered results or errors.
+//
+// Example usage:
+//
+//	out := FilterOk(source, func(v int) bool {
+//	    return v%2 == 0 // filter even numbers
+//	})
+func FilterOk[T any](source <-chan trx.Result[T], pred func(value T) bool, options ...Option) <-chan trx.Result[T] {
+	return Filter(source, func(value T, _ int) (bool, error) {
+		return pred(value), nil
+	}, options...)
+}
+
+// FilterAsync emits only those values from the source channel for which the asynchronous predicate resolves
+// to true. The predicate returns a future: a channel that yields a single boolean result, for example one
+// produced by a permission lookup. The number of futures awaited concurrently is bounded by WithPoolSize,
+// and WithSerialize preserves the source order regardless of the order in which the futures resolve. Only
+// the first result of each future is used; a future that closes without a value yields ErrNoElements.
+// Errors, whether from the source or from a future, are sent downstream wrapped in a trx.Result.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source    - A receive-only channel of trx.Result[T] representing the input stream.
+//	predicate - A function that starts the asynchronous check for a value and its index and returns its future.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing only the values that passed the check, or errors.
+//
+// Example usage:
+//
+//	out := FilterAsync(requests, func(r Request, _ int) <-chan trx.Result[bool] {
+//	    return authz.AllowedAsync(r.User)
+//	}, WithPoolSize(8), WithSerialize())
+func FilterAsync[T any](source <-chan trx.Result[T], predicate func(value T, index int) <-chan trx.Result[bool], options ...Option) <-chan trx.Result[T] {
+	ctx := makeContext(parseOption(options...))
+
+	return Filter(source, func(value T, index int) (bool, error) {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case r, ok := <-predicate(value, index):
+			if !ok {
+				return false, ErrNoElements
+			}
+
+			return r.Get()
+		}
+	}, options...)
+}
+
+// FilterDynamic emits only those values from the source channel that satisfy the active predicate, where the
+// active predicate can be replaced at runtime through the predicates channel. Each predicate received applies
+// to the values read after it; predicates already waiting in the channel are applied before the next value
+// is checked. Until the first predicate arrives, every value is forwarded. When the predicates channel
+// closes, the last predicate stays active. Errors received from the source are sent downstream unchanged.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source     - A receive-only channel of trx.Result[T] representing the input stream.
+//	predicates - A receive-only channel of predicates, each replacing the active one.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values accepted by the active predicate, or errors.
+//
+// Example usage:
+//
+//	levels := make(chan func(Entry) bool)
+//	out := FilterDynamic(entries, levels)
+//	levels <- func(e Entry) bool { return e.Level >= Warn } // Raise the log level at runtime
+func FilterDynamic[T any](source <-chan trx.Result[T], predicates <-chan func(T) bool, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		var predicate func(T) bool
+
+		// update applies the predicates that are already waiting, without blocking.
+		update := func() {
+			for predicates != nil {
+				select {
+				case p, ok := <-predicates:
+					if !ok {
+						predicates = nil
+
+						return
+					}
+
+					predicate = p
+				default:
+					return
+				}
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case p, ok := <-predicates:
+				if !ok {
+					predicates = nil
+
+					continue
+				}
+
+				predicate = p
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				if v.IsErr() {
+					out <- v
+
+					continue
+				}
+
+				update()
+				if predicate == nil || predicate(v.Unwrap()) {
+					out <- v
+				}
+			}
+		}
 	}()
 
 	return out
@@ -95,6 +313,10 @@ func Filter[T any](source <-chan trx.Result[T], predicate func(value T, index in
 // to the output channel. If an error is encountered in the source, it is sent downstream wrapped in a trx.Result,
 // and iteration stops. The function also stops if the source channel is closed or the context is cancelled.
 //
+// By default, a source that is not exhausted is simply no longer read, so its producer stays blocked on its
+// next send. With WithUpstreamCancel, Take cancels the upstream context when it stops early and drains the
+// rest of the source in the background, so the upstream operators shut down.
+//
 // The function supports optional configuration via Option parameters, such as context control.
 //
 // Type Parameters:
@@ -107,6 +329,7 @@ func Filter[T any](source <-chan trx.Result[T], predicate func(value T, index in
 //	n      - The maximum number of values to emit.
 //	options
 //	    - WithBufferSize
+//	    - WithUpstreamCancel
 //	    - WithContext
 //
 // Returns:
@@ -125,6 +348,12 @@ func Take[T any](source <-chan trx.Result[T], n int, options ...Option) <-chan t
 	out := makeResultChannel[T](conf)
 
 	go func() {
+		exhausted := false
+		defer func() {
+			if !exhausted {
+				releaseUpstream(conf, source)
+			}
+		}()
 		defer close(out)
 
 		count := 0
@@ -134,6 +363,8 @@ func Take[T any](source <-chan trx.Result[T], n int, options ...Option) <-chan t
 				return
 			case v, ok := <-source:
 				if !ok {
+					exhausted = true
+
 					return
 				}
 
@@ -153,3 +384,1504 @@ func Take[T any](source <-chan trx.Result[T], n int, options ...Option) <-chan t
 
 	return out
 }
+
+// First emits the first successful value of the source channel and completes. If the source completes
+// without any value, ErrNoElements is emitted instead, so downstream can tell an empty stream apart from a
+// value. If an error is encountered in the source first, it is sent downstream wrapped in a trx.Result, and
+// iteration stops. It is a shortcut for Take(source, 1) that does not silently complete on an empty source.
+//
+// A source that is not exhausted is simply no longer read; with WithUpstreamCancel, First cancels the
+// upstream context once it is done and drains the rest of the source in the background.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	options
+//	    - WithBufferSize
+//	    - WithUpstreamCancel
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits the first value or an error.
+//
+// Example usage:
+//
+//	res := <-First(Map(urls, fetch))
+func First[T any](source <-chan trx.Result[T], options ...Option) <-chan trx.Result[T] {
+	return FirstWhere(source, func(T) bool {
+		return true
+	}, options...)
+}
+
+// FirstWhere emits the first successful value of the source channel for which the predicate returns true,
+// and completes. If the source completes without any matching value, ErrNoElements is emitted instead. If an
+// error is encountered in the source before a match, it is sent downstream wrapped in a trx.Result, and
+// iteration stops. It replaces chaining Filter and Take.
+//
+// A source that is not exhausted is simply no longer read; with WithUpstreamCancel, FirstWhere cancels the
+// upstream context once it is done and drains the rest of the source in the background.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source    - A receive-only channel of trx.Result[T] representing the input stream.
+//	predicate - A function that reports whether a value matches.
+//	options
+//	    - WithBufferSize
+//	    - WithUpstreamCancel
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits the first matching value or an error.
+//
+// Example usage:
+//
+//	res := <-FirstWhere(users, func(u User) bool { return u.IsAdmin })
+func FirstWhere[T any](source <-chan trx.Result[T], predicate func(value T) bool, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		exhausted := false
+		defer func() {
+			if !exhausted {
+				releaseUpstream(conf, source)
+			}
+		}()
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					exhausted = true
+					out <- trx.Err[T](ErrNoElements)
+
+					return
+				}
+
+				val, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					return
+				}
+
+				if predicate(val) {
+					out <- trx.Ok(val)
+
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Last emits the last successful value of the source channel once the source completes. If the source
+// completes without any value, ErrNoElements is emitted instead. If an error is encountered in the source,
+// it is sent downstream wrapped in a trx.Result immediately, and iteration stops.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits the last value or an error.
+//
+// Example usage:
+//
+//	res := <-Last(readings)
+func Last[T any](source <-chan trx.Result[T], options ...Option) <-chan trx.Result[T] {
+	return LastWhere(source, func(T) bool {
+		return true
+	}, options...)
+}
+
+// LastWhere emits the last successful value of the source channel for which the predicate returns true, once
+// the source completes. If no value matched, ErrNoElements is emitted instead. If an error is encountered in
+// the source, it is sent downstream wrapped in a trx.Result immediately, and iteration stops.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source    - A receive-only channel of trx.Result[T] representing the input stream.
+//	predicate - A function that reports whether a value matches.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits the last matching value or an error.
+//
+// Example usage:
+//
+//	res := <-LastWhere(events, func(e Event) bool { return e.Kind == "checkpoint" })
+func LastWhere[T any](source <-chan trx.Result[T], predicate func(value T) bool, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		var last T
+
+		found := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					if !found {
+						out <- trx.Err[T](ErrNoElements)
+
+						return
+					}
+
+					out <- trx.Ok(last)
+
+					return
+				}
+
+				val, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					return
+				}
+
+				if predicate(val) {
+					last = val
+					found = true
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// ElementAt emits only the successful value at the given zero-based position of the source channel, then
+// completes and stops reading the source. Errors do not count as positions. If the source completes before
+// reaching the index, or the index is negative, ErrIndexOutOfRange is emitted instead. If an error is
+// encountered in the source before the index is reached, it is sent downstream wrapped in a trx.Result, and
+// iteration stops.
+//
+// A source that is not exhausted is simply no longer read; with WithUpstreamCancel, ElementAt cancels the
+// upstream context once it is done and drains the rest of the source in the background.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	index  - The zero-based position of the value to emit.
+//	options
+//	    - WithBufferSize
+//	    - WithUpstreamCancel
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits the value at index or an error.
+//
+// Example usage:
+//
+//	res := <-ElementAt(rows, 2) // The third row
+func ElementAt[T any](source <-chan trx.Result[T], index int, options ...Option) <-chan trx.Result[T] {
+	return elementAt(source, index, trx.Err[T](ErrIndexOutOfRange), options...)
+}
+
+// ElementAtOr behaves like ElementAt, but emits defaultValue instead of ErrIndexOutOfRange when the source
+// completes before reaching the index or the index is negative.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source       - A receive-only channel of trx.Result[T] representing the input stream.
+//	index        - The zero-based position of the value to emit.
+//	defaultValue - The value to emit when the source has no value at index.
+//	options
+//	    - WithBufferSize
+//	    - WithUpstreamCancel
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] that emits the value at index, the default value or an error.
+//
+// Example usage:
+//
+//	res := <-ElementAtOr(rows, 2, Row{})
+func ElementAtOr[T any](source <-chan trx.Result[T], index int, defaultValue T, options ...Option) <-chan trx.Result[T] {
+	return elementAt(source, index, trx.Ok(defaultValue), options...)
+}
+
+// elementAt emits the successful value at index of source, or missing if the source has no value there.
+func elementAt[T any](source <-chan trx.Result[T], index int, missing trx.Result[T], options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		exhausted := false
+		defer func() {
+			if !exhausted {
+				releaseUpstream(conf, source)
+			}
+		}()
+		defer close(out)
+
+		if index < 0 {
+			out <- missing
+
+			return
+		}
+
+		position := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					exhausted = true
+					out <- missing
+
+					return
+				}
+
+				val, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					return
+				}
+
+				if position == index {
+					out <- trx.Ok(val)
+
+					return
+				}
+
+				position++
+			}
+		}
+	}()
+
+	return out
+}
+
+// TakePercent emits the first fraction of the successful values of the source channel, for example to sample
+// half of a dataset. The number of values taken is fraction times the number of values in the source,
+// rounded down; fraction is clamped to the range [0, 1].
+//
+// Without WithTotalHint, the size of the source is unknown, so TakePercent buffers every value until the
+// source closes and only then emits the first part: memory grows with the size of the source and nothing is
+// emitted before it completes. With WithTotalHint, it behaves like Take with the computed count and streams
+// values as they arrive. If an error is encountered in the source, it is sent downstream wrapped in a
+// trx.Result, and iteration stops.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source   - A receive-only channel of trx.Result[T] representing the input stream.
+//	fraction - The fraction of values to emit, between 0 and 1.
+//	options
+//	    - WithBufferSize
+//	    - WithTotalHint
+//	    - WithUpstreamCancel
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the first part of the values, or an error.
+//
+// Example usage:
+//
+//	out := TakePercent(FormSlice(records), 0.1, WithTotalHint(len(records))) // The first 10%
+func TakePercent[T any](source <-chan trx.Result[T], fraction float64, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+
+	fraction = min(max(fraction, 0), 1)
+	if conf.total > 0 {
+		return Take(source, fractionOf(fraction, conf.total), options...)
+	}
+
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		buffer := make([]T, 0)
+
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				val, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					return
+				}
+
+				buffer = append(buffer, val)
+			}
+		}
+
+		for _, v := range buffer[:fractionOf(fraction, len(buffer))] {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- trx.Ok(v):
+			}
+		}
+	}()
+
+	return out
+}
+
+// fractionOf returns fraction times n rounded down, tolerating floating-point error so that, for
+// example, 0.29 of 100 is 29 rather than 28.
+func fractionOf(fraction float64, n int) int {
+	return int(math.Floor(fraction*float64(n) + 1e-9))
+}
+
+// Skip ignores the first n successful values from the source channel and forwards the rest. Errors are always
+// forwarded downstream wrapped in a trx.Result, including those encountered among the skipped values, and they
+// do not count towards n, so failures are never hidden. If n is less than or equal to 0, every result is
+// forwarded; if the source has n values or fewer, only its errors are forwarded before the channel is closed.
+// Combined with Take, it allows pagination-style slicing.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	n      - The number of successful values to skip.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the results after the first n values.
+//
+// Example usage:
+//
+//	page := Take(Skip(source, 20), 10) // Values 20 to 29
+func Skip[T any](source <-chan trx.Result[T], n int, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		skipped := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				if v.IsOk() && skipped < n {
+					skipped++
+
+					continue
+				}
+
+				out <- v
+			}
+		}
+	}()
+
+	return out
+}
+
+// TakeUntilValue emits values from the source channel until the sentinel value is seen, then completes
+// without emitting the sentinel. It is a simple data-driven terminator, for example to stop at an
+// end-of-stream marker. If an error is encountered in the source, it is sent downstream wrapped in a
+// trx.Result, and iteration stops. The function also stops if the source channel is closed or the
+// context is cancelled.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel. Must be comparable.
+//
+// Parameters:
+//
+//	source   - A receive-only channel of trx.Result[T] representing the input stream.
+//	sentinel - The value that ends the stream.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values before the sentinel.
+//
+// Example usage:
+//
+//	out := TakeUntilValue(lines, "EOF")
+func TakeUntilValue[T comparable](source <-chan trx.Result[T], sentinel T, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				val, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					return
+				}
+
+				if val == sentinel {
+					return
+				}
+
+				out <- trx.Ok(val)
+			}
+		}
+	}()
+
+	return out
+}
+
+// TakeUntil mirrors the source channel until the notifier emits its first result, whether a value or an
+// error, or closes; it then completes and stops reading the source. It is the event-driven counterpart of
+// Take, for example to collect metrics until a shutdown signal arrives. Errors received from the source are
+// forwarded downstream wrapped in a trx.Result. The function also stops if the source channel is closed or
+// the context is cancelled.
+//
+// A source that is not exhausted is simply no longer read; with WithUpstreamCancel, TakeUntil cancels the
+// upstream context when the notifier fires and drains the rest of the source in the background.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of values emitted by the notifier.
+//
+// Parameters:
+//
+//	source   - A receive-only channel of trx.Result[T] representing the input stream.
+//	notifier - A receive-only channel whose first emission or closing ends the stream.
+//	options
+//	    - WithBufferSize
+//	    - WithUpstreamCancel
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the results received before the notifier fired.
+//
+// Example usage:
+//
+//	out := TakeUntil(metrics, shutdown)
+func TakeUntil[T, U any](source <-chan trx.Result[T], notifier <-chan trx.Result[U], options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-notifier:
+				releaseUpstream(conf, source)
+
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				out <- v
+			}
+		}
+	}()
+
+	return out
+}
+
+// SkipUntil discards the successful values of the source channel until the notifier emits its first result,
+// whether a value or an error, and forwards everything received afterwards. It complements TakeUntil and lets
+// a stream ignore data before a trigger event without knowing how many items to skip. Errors received from
+// the source are always forwarded downstream wrapped in a trx.Result, including those received while values
+// are being skipped. If the notifier closes without emitting, values are skipped until the source closes.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of values emitted by the notifier.
+//
+// Parameters:
+//
+//	source   - A receive-only channel of trx.Result[T] representing the input stream.
+//	notifier - A receive-only channel whose first emission starts forwarding values.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the errors and the values received after the notification.
+//
+// Example usage:
+//
+//	out := SkipUntil(readings, warmedUp)
+func SkipUntil[T, U any](source <-chan trx.Result[T], notifier <-chan trx.Result[U], options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		open := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-notifier:
+				open = ok
+				notifier = nil
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				if v.IsOk() && !open {
+					continue
+				}
+
+				out <- v
+			}
+		}
+	}()
+
+	return out
+}
+
+// DebounceTime emits a value from the source channel only once d has elapsed without a newer value arriving.
+// Each new value restarts the timer and replaces the pending one, so the most recent value of a burst wins,
+// as is common for UI input or noisy sensors. When the source completes, a pending value is emitted right
+// away. Errors received from the source bypass the debouncing: they are sent downstream immediately, wrapped
+// in a trx.Result, and do not affect the pending value.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	d      - The quiet period required before a value is emitted.
+//	options
+//	    - WithBufferSize
+//	    - WithClock
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the debounced values and errors.
+//
+// Example usage:
+//
+//	out := DebounceTime(keystrokes, 300*time.Millisecond)
+func DebounceTime[T any](source <-chan trx.Result[T], d time.Duration, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		var (
+			pending T
+			timer   <-chan time.Time // nil while no value is pending
+		)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer:
+				timer = nil
+				out <- trx.Ok(pending)
+			case v, ok := <-source:
+				if !ok {
+					if timer != nil {
+						out <- trx.Ok(pending)
+					}
+
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					continue
+				}
+
+				pending = value
+				timer = conf.clock.After(d)
+			}
+		}
+	}()
+
+	return out
+}
+
+// ThrottleTime emits the first value from the source channel, then ignores the following values for the
+// duration d, then emits the next value and repeats, capping the emission rate of a chatty source
+// (leading-edge throttling). With WithTrailing, the last value ignored during a window is also emitted when
+// the window ends, which starts a new window, and a value still pending when the source completes is emitted
+// before closing. Errors received from the source bypass the throttling: they are sent downstream
+// immediately, wrapped in a trx.Result, and do not start a window.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	d      - The duration of the silent window that follows each emission.
+//	options
+//	    - WithBufferSize
+//	    - WithTrailing
+//	    - WithClock
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the throttled values and errors.
+//
+// Example usage:
+//
+//	out := ThrottleTime(scrollEvents, 100*time.Millisecond, WithTrailing())
+func ThrottleTime[T any](source <-chan trx.Result[T], d time.Duration, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		var (
+			pending T
+			window  <-chan time.Time // nil while not throttling
+		)
+
+		hasPending := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-window:
+				window = nil
+
+				if hasPending {
+					hasPending = false
+					out <- trx.Ok(pending)
+					window = conf.clock.After(d)
+				}
+			case v, ok := <-source:
+				if !ok {
+					if hasPending {
+						out <- trx.Ok(pending)
+					}
+
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					continue
+				}
+
+				if window == nil {
+					out <- trx.Ok(value)
+					window = conf.clock.After(d)
+
+					continue
+				}
+
+				if conf.trailing {
+					pending = value
+					hasPending = true
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// SampleTime emits, on each tick of a d interval, the most recent value received from the source channel since
+// the previous tick, and nothing if no value arrived. Unlike ThrottleTime, the emission timing is driven by the
+// clock rather than by incoming values, which makes it suited to periodic snapshots of a fast stream. With
+// WithFlushOnComplete, a value that has not been sampled when the source completes is emitted before closing.
+// If an error is received from the source, it is sent downstream wrapped in a trx.Result and sampling stops.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	d      - The sampling period.
+//	options
+//	    - WithBufferSize
+//	    - WithFlushOnComplete
+//	    - WithClock
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the sampled values or errors.
+//
+// Example usage:
+//
+//	out := SampleTime(prices, time.Second)
+func SampleTime[T any](source <-chan trx.Result[T], d time.Duration, options ...Option) <-chan trx.Result[T] {
+	return SampleTimeOrSignal(source, d, nil, options...)
+}
+
+// SampleTimeOrSignal emits the most recent value from the source channel whenever the duration d elapses
+// or the trigger channel fires, whichever happens first. A value is emitted at most once: if no new value
+// has arrived since the previous sample, nothing is emitted. With WithFlushOnComplete, a value that has not
+// been sampled when the source completes is emitted before closing. If an error is received from the source,
+// it is sent downstream wrapped in a trx.Result and sampling stops.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	d       - The sampling period.
+//	trigger - A channel that requests an immediate sample each time it receives a value.
+//	options
+//	    - WithBufferSize
+//	    - WithFlushOnComplete
+//	    - WithClock
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the sampled values or errors.
+//
+// Example usage:
+//
+//	out := SampleTimeOrSignal(source, time.Second, refreshClicks)
+func SampleTimeOrSignal[T any](source <-chan trx.Result[T], d time.Duration, trigger <-chan struct{}, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		tick := conf.clock.After(d)
+
+		var latest T
+		hasValue := false
+
+		// sample emits the latest value if it has not been emitted yet, and reports false on cancellation.
+		sample := func() bool {
+			if !hasValue {
+				return true
+			}
+
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- trx.Ok(latest):
+				hasValue = false
+
+				return true
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick:
+				tick = conf.clock.After(d)
+
+				if !sample() {
+					return
+				}
+			case _, ok := <-trigger:
+				if !ok {
+					trigger = nil
+
+					continue
+				}
+
+				if !sample() {
+					return
+				}
+			case v, ok := <-source:
+				if !ok {
+					if conf.flush {
+						sample()
+					}
+
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					select {
+					case <-ctx.Done():
+					case out <- trx.Err[T](err):
+					}
+
+					return
+				}
+
+				latest = value
+				hasValue = true
+			}
+		}
+	}()
+
+	return out
+}
+
+// Distinct emits a value from the source channel only the first time it is seen, for example to deduplicate
+// IDs across an entire stream. Errors received from the source are always forwarded and are not tracked.
+//
+// Every distinct value is remembered, so memory grows with the number of distinct values in the stream.
+// WithMaxKeys bounds it by forgetting the least recently seen values, which may then be emitted again.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel (must be comparable).
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	options
+//	    - WithBufferSize
+//	    - WithMaxKeys
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the first occurrence of each value, and errors.
+//
+// Example usage:
+//
+//	out := Distinct(FormSlice([]int{1, 2, 1, 3, 2})) // 1, 2, 3
+func Distinct[T comparable](source <-chan trx.Result[T], options ...Option) <-chan trx.Result[T] {
+	return DistinctBy(source, func(value T) T {
+		return value
+	}, options...)
+}
+
+// DistinctBy emits a value from the source channel only the first time its key is seen. It works like
+// Distinct for types that are not comparable, or when only part of a value identifies it. Errors received
+// from the source are always forwarded and are not tracked.
+//
+// Every distinct key is remembered, so memory grows with the number of distinct keys in the stream.
+// WithMaxKeys bounds it by forgetting the least recently seen keys, whose values may then be emitted again.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	K - The type of the keys (must be comparable).
+//
+// Parameters:
+//
+//	source      - A receive-only channel of trx.Result[T] representing the input stream.
+//	keySelector - A function that returns the key of a value.
+//	options
+//	    - WithBufferSize
+//	    - WithMaxKeys
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the first value of each key, and errors.
+//
+// Example usage:
+//
+//	out := DistinctBy(orders, func(o Order) string { return o.ID })
+func DistinctBy[T any, K comparable](source <-chan trx.Result[T], keySelector func(value T) K, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		seen := newKeySet[K](conf.maxKeys)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					continue
+				}
+
+				if seen.add(keySelector(value)) {
+					out <- trx.Ok(value)
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// keySet is a set of keys that, when capped, evicts the least recently seen key to make room for a new one.
+type keySet[K comparable] struct {
+	capacity int // Maximum number of keys (0 = unlimited)
+	order    *list.List
+	elements map[K]*list.Element
+}
+
+func newKeySet[K comparable](capacity int) *keySet[K] {
+	return &keySet[K]{
+		capacity: capacity,
+		order:    list.New(),
+		elements: make(map[K]*list.Element),
+	}
+}
+
+// add records key as the most recently seen and reports whether it was not in the set yet.
+func (s *keySet[K]) add(key K) bool {
+	if e, ok := s.elements[key]; ok {
+		s.order.MoveToFront(e)
+
+		return false
+	}
+
+	if s.capacity > 0 && s.order.Len() >= s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.elements, oldest.Value.(K))
+	}
+
+	s.elements[key] = s.order.PushFront(key)
+
+	return true
+}
+
+// DistinctUntilChanged suppresses consecutive duplicate values from the source channel, emitting a value only
+// when it differs from the previously emitted one. This suits event streams where the same state is reported
+// repeatedly. Errors received from the source are always forwarded and do not affect the deduplication.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel (must be comparable).
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the changed values and errors.
+//
+// Example usage:
+//
+//	out := DistinctUntilChanged(FormSlice([]int{1, 1, 2, 2, 1})) // 1, 2, 1
+func DistinctUntilChanged[T comparable](source <-chan trx.Result[T], options ...Option) <-chan trx.Result[T] {
+	return DistinctUntilChangedBy(source, func(value T) T {
+		return value
+	}, options...)
+}
+
+// DistinctUntilChangedBy suppresses consecutive values from the source channel whose key is equal to the key
+// of the previously emitted value. It works like DistinctUntilChanged for types that are not comparable, or
+// when only part of a value matters. Errors received from the source are always forwarded and do not affect
+// the deduplication.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	K - The type of the keys (must be comparable).
+//
+// Parameters:
+//
+//	source      - A receive-only channel of trx.Result[T] representing the input stream.
+//	keySelector - A function that returns the key of a value.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values whose key changed, and errors.
+//
+// Example usage:
+//
+//	out := DistinctUntilChangedBy(readings, func(r Reading) string { return r.Status })
+func DistinctUntilChangedBy[T any, K comparable](source <-chan trx.Result[T], keySelector func(value T) K, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		var last K
+
+		hasLast := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					continue
+				}
+
+				key := keySelector(value)
+				if hasLast && key == last {
+					continue
+				}
+
+				last = key
+				hasLast = true
+
+				out <- trx.Ok(value)
+			}
+		}
+	}()
+
+	return out
+}
+
+// DistinctUntilChangedTTL suppresses consecutive duplicate values from the source channel, but re-emits an
+// unchanged value once ttl has elapsed since the last emission. This turns a stream of states into a stream
+// of changes that still carries a periodic "still the same" confirmation. Errors received from the source
+// are always forwarded and do not affect the deduplication.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel (must be comparable).
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	ttl    - The time after which an unchanged value is emitted again.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//	    - WithClock
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the changed or refreshed values and errors.
+//
+// Example usage:
+//
+//	out := DistinctUntilChangedTTL(status, time.Minute)
+func DistinctUntilChangedTTL[T comparable](source <-chan trx.Result[T], ttl time.Duration, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		var (
+			last     T
+			lastTime time.Time
+		)
+
+		hasLast := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					continue
+				}
+
+				now := conf.clock.Now()
+				if hasLast && value == last && now.Sub(lastTime) < ttl {
+					continue
+				}
+
+				last = value
+				lastTime = now
+				hasLast = true
+
+				out <- trx.Ok(value)
+			}
+		}
+	}()
+
+	return out
+}
+
+// EveryNth emits every nth value from the source channel, starting with the first one (index % n == 0),
+// and drops the rest. This is a simple count-based downsampler for high-frequency data. Errors received
+// from the source are always forwarded and do not count as values. A non-positive n forwards every value.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	n      - The sampling step.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing every nth value and all errors.
+//
+// Example usage:
+//
+//	out := EveryNth(samples, 10) // Keep one sample out of ten
+func EveryNth[T any](source <-chan trx.Result[T], n int, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	if n <= 0 {
+		n = 1
+	}
+
+	go func() {
+		defer close(out)
+
+		index := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				if v.IsErr() {
+					out <- v
+
+					continue
+				}
+
+				if index%n == 0 {
+					out <- v
+				}
+
+				index++
+			}
+		}
+	}()
+
+	return out
+}
+
+// DistinctWithinCount suppresses a value from the source channel if it is equal to one of the last lastN
+// emitted values, deduplicating over a sliding window measured in emissions rather than time. Memory is
+// bounded by lastN. Errors received from the source are always forwarded and do not enter the window.
+// A non-positive lastN forwards every value.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel (must be comparable).
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	lastN  - The number of most recent emissions a value is compared against.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the values not seen recently and all errors.
+//
+// Example usage:
+//
+//	out := DistinctWithinCount(ids, 1000)
+func DistinctWithinCount[T comparable](source <-chan trx.Result[T], lastN int, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		window := make([]T, 0, max(lastN, 0))
+		counts := make(map[T]int)
+		next := 0 // Position of the oldest value once the window is full
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					continue
+				}
+
+				if lastN <= 0 {
+					out <- trx.Ok(value)
+
+					continue
+				}
+
+				if counts[value] > 0 {
+					continue
+				}
+
+				if len(window) < lastN {
+					window = append(window, value)
+				} else {
+					oldest := window[next]
+					if counts[oldest]--; counts[oldest] == 0 {
+						delete(counts, oldest)
+					}
+
+					window[next] = value
+					next = (next + 1) % lastN
+				}
+				counts[value]++
+
+				out <- trx.Ok(value)
+			}
+		}
+	}()
+
+	return out
+}
+
+// IgnoreElements drops every successful value of the source channel and forwards only its errors, then
+// closes the output channel when the source completes. It is useful when only the completion or the
+// failures of a side-effecting stream matter, not its values: a source without errors yields a channel
+// that is closed without emitting anything.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing only the errors of the source.
+//
+// Example usage:
+//
+//	for res := range IgnoreElements(Map(records, save)) {
+//	    log.Println(res.Err())
+//	}
+func IgnoreElements[T any](source <-chan trx.Result[T], options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				if v.IsErr() {
+					out <- v
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// DropUntilOk discards the leading error results of the source channel and starts forwarding from the first
+// successful value on, including any later errors. This is useful for sources that emit startup errors until
+// they stabilize, such as a connection that needs a few attempts.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the results from the first successful value on.
+//
+// Example usage:
+//
+//	out := DropUntilOk(readings)
+func DropUntilOk[T any](source <-chan trx.Result[T], options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		forwarding := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				if !forwarding && v.IsErr() {
+					continue
+				}
+
+				forwarding = true
+				out <- v
+			}
+		}
+	}()
+
+	return out
+}
@@ -1,13 +1,16 @@
 package op_test
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/foreveralonet/trx"
 	"github.com/foreveralonet/trx/op"
+	"github.com/foreveralonet/trx/trxtest"
 )
 
 var _ = Describe("Filtering Operations", func() {
@@ -151,6 +154,205 @@ var _ = Describe("Filtering Operations", func() {
 				Expect(results).To(Equal(expectedValues))
 			})
 		})
+
+		Context("with WithEmitCancellationError", func() {
+			It("should not block when the consumer stopped reading before cancelling", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancelCause(context.Background())
+
+					out := op.Filter(op.Range(0, 10000, op.WithContext(ctx)), func(v int, _ int) (bool, error) {
+						return true, nil
+					}, op.WithPoolSize(8), op.WithContext(ctx), op.WithEmitCancellationError())
+
+					count := 0
+					for range out {
+						count++
+						if count == 5 {
+							break
+						}
+					}
+
+					cancel(errors.New("shutting down"))
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should emit the cancellation cause before closing", func() {
+				cause := errors.New("shutting down")
+				ctx, cancel := context.WithCancelCause(context.Background())
+
+				out := op.Filter(make(chan trx.Result[int]), func(int, int) (bool, error) {
+					return true, nil
+				}, op.WithContext(ctx), op.WithEmitCancellationError())
+				cancel(cause)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Err()).To(Equal(cause))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when limiting the number of emissions", func() {
+			It("should emit exactly n matching values", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel() // Release the Range producer left blocked once the limit is reached
+
+					out := op.Filter(op.Range(0, 100, op.WithContext(ctx)), func(value int, index int) (bool, error) {
+						return value%2 == 0, nil
+					}, op.WithLimit(3))
+
+					results := make([]int, 0)
+					for result := range out {
+						results = append(results, result.Unwrap())
+					}
+
+					Expect(results).To(Equal([]int{0, 2, 4}))
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("FilterOk", func() {
+		Context("when filtering with an infallible predicate", func() {
+			It("should keep only elements that match the predicate", func() {
+				source := op.Range(0, 10)
+				out := op.FilterOk(source, func(value int) bool {
+					return value%2 == 0 // Keep even numbers
+				})
+
+				expectedValues := []int{0, 2, 4, 6, 8}
+				results := make([]int, 0)
+
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+
+					value, err := result.Get()
+					Expect(err).To(BeNil())
+					results = append(results, value)
+				}
+
+				Expect(results).To(Equal(expectedValues))
+			})
+		})
+	})
+
+	Describe("FilterAsync", func() {
+		isEven := func(value int, index int) <-chan trx.Result[bool] {
+			result := make(chan trx.Result[bool], 1)
+
+			go func() {
+				defer close(result)
+
+				// Later values resolve first
+				time.Sleep(time.Duration(8-value) * 5 * time.Millisecond)
+				result <- trx.Ok(value%2 == 0)
+			}()
+
+			return result
+		}
+
+		Context("when predicates resolve out of order", func() {
+			It("should keep every passing value", func() {
+				out := op.FilterAsync(op.Range(1, 6), isEven, op.WithPoolSize(6))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(ConsistOf(2, 4, 6))
+			})
+
+			It("should preserve the source order when serialized", func() {
+				out := op.FilterAsync(op.Range(1, 6), isEven, op.WithPoolSize(6), op.WithSerialize())
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{2, 4, 6}))
+			})
+		})
+
+		Context("when a future fails", func() {
+			It("should emit the error", func() {
+				lookupError := errors.New("lookup error")
+				out := op.FilterAsync(op.Range(0, 1), func(value int, index int) <-chan trx.Result[bool] {
+					result := make(chan trx.Result[bool], 1)
+					result <- trx.Err[bool](lookupError)
+					close(result)
+
+					return result
+				})
+
+				result := <-out
+				Expect(result.Err()).To(Equal(lookupError))
+			})
+		})
+	})
+
+	Describe("FilterDynamic", func() {
+		Context("when the predicate changes mid-stream", func() {
+			It("should apply the new predicate to subsequent values", func() {
+				source := make(chan trx.Result[int])
+				predicates := make(chan func(int) bool)
+
+				out := op.FilterDynamic(source, predicates, op.WithBufferSize(4))
+
+				var result trx.Result[int]
+
+				source <- trx.Ok(1)
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(1))
+
+				predicates <- func(v int) bool { return v%2 == 0 }
+				source <- trx.Ok(3)
+				source <- trx.Ok(4)
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(4))
+
+				predicates <- func(v int) bool { return v%2 != 0 }
+				close(predicates)
+				source <- trx.Ok(5)
+				source <- trx.Ok(6)
+				source <- trx.Ok(7)
+				close(source)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{5, 7}))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should forward the error regardless of the predicate", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(1)
+				close(source)
+
+				predicates := make(chan func(int) bool, 1)
+				predicates <- func(int) bool { return false }
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.FilterDynamic(source, predicates) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].Err()).To(Equal(testError))
+			})
+		})
 	})
 
 	Describe("Take", func() {
@@ -269,6 +471,1168 @@ var _ = Describe("Filtering Operations", func() {
 				Expect(results).To(Equal(expectedValues))
 			})
 		})
+
+		Context("with WithUpstreamCancel", func() {
+			It("should stop the upstream interval once the limit is reached", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+
+					interval := op.Interval(5*time.Millisecond, op.WithContext(ctx))
+					out := op.Take(interval, 5, op.WithUpstreamCancel(cancel))
+
+					count := 0
+					for range out {
+						count++
+					}
+					Expect(count).To(Equal(5))
+
+					Eventually(ctx.Done()).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should not cancel the upstream when the source is exhausted", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				out := op.Take(op.Range(0, 3), 5, op.WithUpstreamCancel(cancel))
+				for range out {
+				}
+
+				Consistently(ctx.Done(), 20*time.Millisecond).ShouldNot(BeClosed())
+			})
+		})
+	})
+
+	Describe("First", func() {
+		Context("when the source emits values", func() {
+			It("should emit only the first value", func() {
+				results := make([]int, 0)
+				for result := range op.First(op.Range(5, 3)) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{5}))
+			})
+		})
+
+		Context("when the source is empty", func() {
+			It("should emit ErrNoElements", func() {
+				results := make([]trx.Result[int], 0)
+				for result := range op.First(op.Empty[int]()) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].Err()).To(MatchError(op.ErrNoElements))
+			})
+		})
+
+		Context("with WithUpstreamCancel", func() {
+			It("should stop the upstream interval after the first value", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+
+					interval := op.Interval(5*time.Millisecond, op.WithContext(ctx))
+					for range op.First(interval, op.WithUpstreamCancel(cancel)) {
+					}
+
+					Eventually(ctx.Done()).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("FirstWhere", func() {
+		Context("when a value matches", func() {
+			It("should emit the first matching value", func() {
+				results := make([]int, 0)
+				for result := range op.FirstWhere(op.Range(1, 10), func(v int) bool { return v%4 == 0 }) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{4}))
+			})
+		})
+
+		Context("when no value matches", func() {
+			It("should emit ErrNoElements", func() {
+				out := op.FirstWhere(op.Range(1, 3), func(v int) bool { return v > 10 })
+
+				result := <-out
+				Expect(result.Err()).To(MatchError(op.ErrNoElements))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source fails before a match", func() {
+			It("should emit the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(1)
+				close(source)
+
+				out := op.FirstWhere(source, func(int) bool { return true })
+
+				result := <-out
+				Expect(result.Err()).To(Equal(testError))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("Last", func() {
+		Context("when the source emits values", func() {
+			It("should emit only the last value after completion", func() {
+				source := make(chan trx.Result[int])
+				out := op.Last(source)
+
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				Consistently(out, 20*time.Millisecond).ShouldNot(Receive())
+
+				close(source)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(2))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source is empty", func() {
+			It("should emit ErrNoElements", func() {
+				result := <-op.Last(op.Empty[int]())
+				Expect(result.Err()).To(MatchError(op.ErrNoElements))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should emit the error immediately and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int])
+				out := op.Last(source)
+
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Err()).To(Equal(testError))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("LastWhere", func() {
+		Context("when values match", func() {
+			It("should emit the last matching value", func() {
+				results := make([]int, 0)
+				for result := range op.LastWhere(op.Range(1, 10), func(v int) bool { return v%3 == 0 }) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{9}))
+			})
+		})
+
+		Context("when no value matches", func() {
+			It("should emit ErrNoElements", func() {
+				result := <-op.LastWhere(op.Range(1, 3), func(v int) bool { return v > 10 })
+				Expect(result.Err()).To(MatchError(op.ErrNoElements))
+			})
+		})
+	})
+
+	Describe("ElementAt", func() {
+		Context("when the source reaches the index", func() {
+			It("should emit only the value at that position and stop reading", func() {
+				source := make(chan trx.Result[string], 5)
+				for _, v := range []string{"a", "b", "c", "d", "e"} {
+					source <- trx.Ok(v)
+				}
+				close(source)
+
+				results := make([]string, 0)
+				for result := range op.ElementAt(source, 2) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"c"}))
+				Expect(source).To(HaveLen(2))
+			})
+		})
+
+		Context("when the source is too short", func() {
+			It("should emit ErrIndexOutOfRange", func() {
+				result := <-op.ElementAt(op.Range(0, 3), 3)
+				Expect(result.Err()).To(MatchError(op.ErrIndexOutOfRange))
+			})
+
+			It("should emit ErrIndexOutOfRange for a negative index", func() {
+				result := <-op.ElementAt(op.Range(0, 3), -1)
+				Expect(result.Err()).To(MatchError(op.ErrIndexOutOfRange))
+			})
+		})
+
+		Context("when the source fails before the index", func() {
+			It("should emit the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(0)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(2)
+				close(source)
+
+				out := op.ElementAt(source, 1)
+
+				result := <-out
+				Expect(result.Err()).To(Equal(testError))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("ElementAtOr", func() {
+		Context("when the source is too short", func() {
+			It("should emit the default value", func() {
+				result := <-op.ElementAtOr(op.Range(0, 3), 10, -1)
+				Expect(result.Unwrap()).To(Equal(-1))
+			})
+		})
+
+		Context("when the source reaches the index", func() {
+			It("should emit the value at that position", func() {
+				result := <-op.ElementAtOr(op.Range(0, 3), 1, -1)
+				Expect(result.Unwrap()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("TakePercent", func() {
+		Context("when the total is unknown", func() {
+			It("should take half of a 10-element source", func() {
+				results := make([]int, 0)
+				for result := range op.TakePercent(op.Range(0, 10), 0.5) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1, 2, 3, 4}))
+			})
+
+			It("should not emit before the source completes", func() {
+				source := make(chan trx.Result[int])
+				out := op.TakePercent(source, 0.5)
+
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				Consistently(out, 20*time.Millisecond).ShouldNot(Receive())
+
+				close(source)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(1))
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should emit the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(3)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.TakePercent(source, 1) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].Err()).To(Equal(testError))
+			})
+		})
+
+		Context("with WithTotalHint", func() {
+			It("should stream the first part without waiting for the source", func() {
+				source := make(chan trx.Result[int])
+				out := op.TakePercent(source, 0.29, op.WithTotalHint(100))
+
+				go func() {
+					for i := 0; i < 29; i++ {
+						source <- trx.Ok(i)
+					}
+				}()
+
+				count := 0
+				for range out {
+					count++
+				}
+
+				Expect(count).To(Equal(29))
+			})
+		})
+	})
+
+	Describe("Skip", func() {
+		Context("when skipping values", func() {
+			It("should forward the values after the first n", func() {
+				results := make([]int, 0)
+				for result := range op.Skip(op.Range(0, 6), 4) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{4, 5}))
+			})
+
+			It("should forward everything when n is not positive", func() {
+				results := make([]int, 0)
+				for result := range op.Skip(op.Range(0, 3), 0) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1, 2}))
+			})
+
+			It("should close empty when the source is shorter than n", func() {
+				out := op.Skip(op.Range(0, 3), 10)
+
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should slice a page together with Take", func() {
+				results := make([]int, 0)
+				for result := range op.Take(op.Skip(op.Range(0, 100), 20), 3) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{20, 21, 22}))
+			})
+		})
+
+		Context("when errors occur among the skipped values", func() {
+			It("should forward them without counting them", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 4)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(2)
+				source <- trx.Ok(3)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.Skip(source, 2) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(2))
+				Expect(results[0].Err()).To(Equal(testError))
+				Expect(results[1].Unwrap()).To(Equal(3))
+			})
+		})
+	})
+
+	Describe("TakeUntilValue", func() {
+		Context("when the sentinel appears mid-stream", func() {
+			It("should emit values before it and nothing after it", func() {
+				out := op.TakeUntilValue(op.FormSlice([]string{"a", "b", "END", "c", "d"}), "END")
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"a", "b"}))
+			})
+		})
+
+		Context("when the sentinel never appears", func() {
+			It("should emit every value", func() {
+				out := op.TakeUntilValue(op.Range(0, 3), -1)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1, 2}))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should emit the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(1)
+				close(source)
+
+				out := op.TakeUntilValue(source, 0)
+
+				result := <-out
+				Expect(result.Err()).To(Equal(testError))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("TakeUntil", func() {
+		Context("when the notifier emits mid-stream", func() {
+			It("should mirror the source until the notification and then complete", func() {
+				source := make(chan trx.Result[int])
+				notifier := make(chan trx.Result[struct{}])
+
+				out := op.TakeUntil(source, notifier)
+
+				var result trx.Result[int]
+				source <- trx.Ok(1)
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(1))
+
+				source <- trx.Ok(2)
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(2))
+
+				notifier <- trx.Ok(struct{}{})
+				Eventually(out).Should(BeClosed())
+				Consistently(source, 20*time.Millisecond).ShouldNot(BeSent(trx.Ok(3)))
+			})
+		})
+
+		Context("when the notifier closes without emitting", func() {
+			It("should complete", func() {
+				notifier := make(chan trx.Result[int])
+				close(notifier)
+
+				out := op.TakeUntil(make(chan trx.Result[int]), notifier)
+
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the notifier never fires", func() {
+			It("should forward every result, including errors", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(3)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.TakeUntil(source, make(chan trx.Result[string])) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(3))
+				Expect(results[1].Err()).To(Equal(testError))
+				Expect(results[2].Unwrap()).To(Equal(3))
+			})
+		})
+
+		Context("with WithUpstreamCancel", func() {
+			It("should stop the upstream interval once the notifier fires", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+
+					notifier := make(chan trx.Result[struct{}])
+					interval := op.Interval(5*time.Millisecond, op.WithContext(ctx))
+					out := op.TakeUntil(interval, notifier, op.WithUpstreamCancel(cancel))
+
+					Eventually(out).Should(Receive())
+					close(notifier)
+					for range out {
+					}
+
+					Eventually(ctx.Done()).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("SkipUntil", func() {
+		Context("when the notifier emits mid-stream", func() {
+			It("should skip values before the notification and forward the rest", func() {
+				source := make(chan trx.Result[int])
+				notifier := make(chan trx.Result[string])
+
+				out := op.SkipUntil(source, notifier, op.WithBufferSize(4))
+
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				notifier <- trx.Ok("go")
+				source <- trx.Ok(3)
+				source <- trx.Ok(4)
+				close(source)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{3, 4}))
+			})
+		})
+
+		Context("when the source fails during the skip phase", func() {
+			It("should still forward the error", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(3)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.SkipUntil(source, make(chan trx.Result[struct{}])) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].Err()).To(Equal(testError))
+			})
+		})
+
+		Context("when the notifier closes without emitting", func() {
+			It("should keep skipping values", func() {
+				notifier := make(chan trx.Result[int])
+				close(notifier)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.SkipUntil(op.Range(0, 3), notifier) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("DebounceTime", func() {
+		Context("when values arrive in bursts", func() {
+			It("should emit the last value of each burst after the quiet period", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				source := make(chan trx.Result[int])
+				out := op.DebounceTime(source, time.Second, op.WithClock(clock))
+
+				source <- trx.Ok(1)
+				clock.Advance(500 * time.Millisecond)
+				source <- trx.Ok(2)
+				clock.Advance(500 * time.Millisecond)
+				source <- trx.Ok(3)
+				Consistently(out, 20*time.Millisecond).ShouldNot(Receive())
+
+				clock.Advance(time.Second)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(3))
+
+				source <- trx.Ok(4)
+				clock.Advance(time.Second)
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(4))
+
+				close(source)
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source completes with a pending value", func() {
+			It("should flush the pending value", func() {
+				results := make([]int, 0)
+				for result := range op.DebounceTime(op.Range(0, 5), time.Hour) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{4}))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should emit the error immediately and keep the pending value", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int])
+				out := op.DebounceTime(source, time.Second, op.WithClock(clock))
+
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Err()).To(Equal(testError))
+
+				clock.Advance(time.Second)
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(1))
+
+				close(source)
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("ThrottleTime", func() {
+		t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		Context("with leading-edge throttling", func() {
+			It("should emit the first value of each window and drop the rest", func() {
+				clock := newFakeClock(t0)
+				source := make(chan trx.Result[int])
+				out := op.ThrottleTime(source, time.Second, op.WithClock(clock), op.WithBufferSize(4))
+
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				source <- trx.Ok(3)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(1))
+
+				clock.Advance(time.Second)
+				Consistently(out, 20*time.Millisecond).ShouldNot(Receive())
+
+				source <- trx.Ok(4)
+				source <- trx.Ok(5)
+				close(source)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{4}))
+			})
+		})
+
+		Context("with WithTrailing", func() {
+			It("should also emit the last value seen during the window", func() {
+				clock := newFakeClock(t0)
+				source := make(chan trx.Result[int])
+				out := op.ThrottleTime(source, time.Second, op.WithClock(clock), op.WithTrailing(), op.WithBufferSize(4))
+
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				source <- trx.Ok(3)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(1))
+
+				clock.Advance(time.Second)
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(3))
+
+				// The trailing emission started a new window
+				Eventually(clock.Waits).Should(HaveLen(2))
+				source <- trx.Ok(4)
+				Consistently(out, 20*time.Millisecond).ShouldNot(Receive())
+
+				close(source)
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(4))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should emit the error immediately", func() {
+				clock := newFakeClock(t0)
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int])
+				out := op.ThrottleTime(source, time.Second, op.WithClock(clock), op.WithBufferSize(4))
+
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(2))
+				Expect(results[0].Unwrap()).To(Equal(1))
+				Expect(results[1].Err()).To(Equal(testError))
+			})
+		})
+	})
+
+	Describe("SampleTime", func() {
+		Context("when the source is faster than the period", func() {
+			It("should emit the most recent value on each tick", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				source := make(chan trx.Result[int])
+				out := op.SampleTime(source, time.Second, op.WithClock(clock))
+
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				source <- trx.Ok(3)
+
+				clock.Advance(time.Second)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(3))
+
+				// Nothing arrived since the last tick
+				Eventually(clock.Waits).Should(HaveLen(2))
+				clock.Advance(time.Second)
+				Consistently(out, 30*time.Millisecond).ShouldNot(Receive())
+
+				close(source)
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source completes with an unsampled value", func() {
+			It("should drop it by default", func() {
+				source := make(chan trx.Result[int])
+				out := op.SampleTime(source, time.Hour)
+
+				source <- trx.Ok(1)
+				close(source)
+
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should flush it with WithFlushOnComplete", func() {
+				source := make(chan trx.Result[int])
+				out := op.SampleTime(source, time.Hour, op.WithFlushOnComplete())
+
+				source <- trx.Ok(1)
+				close(source)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Unwrap()).To(Equal(1))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("SampleTimeOrSignal", func() {
+		Context("when the trigger fires between time windows", func() {
+			It("should emit an extra sample on the trigger", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				source := make(chan trx.Result[int])
+				trigger := make(chan struct{})
+
+				out := op.SampleTimeOrSignal(source, time.Second, trigger, op.WithClock(clock))
+
+				source <- trx.Ok(1)
+				trigger <- struct{}{}
+
+				// The trigger samples without the clock moving
+				result := <-out
+				Expect(result.Unwrap()).To(Equal(1))
+
+				source <- trx.Ok(2)
+				source <- trx.Ok(3)
+				Consistently(out, 30*time.Millisecond).ShouldNot(Receive())
+
+				clock.Advance(time.Second)
+
+				result = <-out
+				Expect(result.Unwrap()).To(Equal(3))
+
+				close(source)
+
+				_, ok := <-out
+				Expect(ok).To(BeFalse())
+			})
+
+			It("should not emit when no new value arrived", func() {
+				source := make(chan trx.Result[int])
+				trigger := make(chan struct{})
+
+				out := op.SampleTimeOrSignal(source, 20*time.Millisecond, trigger)
+
+				source <- trx.Ok(1)
+				result := <-out
+				Expect(result.Unwrap()).To(Equal(1))
+
+				trigger <- struct{}{}
+				Consistently(out, 60*time.Millisecond).ShouldNot(Receive())
+
+				close(source)
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should propagate the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 1)
+				source <- trx.Err[int](testError)
+
+				out := op.SampleTimeOrSignal(source, time.Second, nil)
+
+				result := <-out
+				Expect(result.Err()).To(Equal(testError))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the context is cancelled while the consumer has stopped reading", func() {
+			It("should stop without leaking goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+					source := make(chan trx.Result[int])
+					trigger := make(chan struct{})
+
+					op.SampleTimeOrSignal(source, time.Second, trigger, op.WithClock(clock), op.WithContext(ctx))
+
+					source <- trx.Ok(1)
+					trigger <- struct{}{} // The sample is never read
+
+					cancel()
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Distinct", func() {
+		Context("when the source repeats values", func() {
+			It("should emit each value only the first time it is seen", func() {
+				results := make([]int, 0)
+				for result := range op.Distinct(op.FormSlice([]int{1, 2, 1, 3, 2, 3, 4, 1})) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{1, 2, 3, 4}))
+			})
+		})
+
+		Context("when the source contains errors", func() {
+			It("should forward every error", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 4)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(1)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.Distinct(source) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(3))
+				Expect(results[1].Err()).To(Equal(testError))
+				Expect(results[2].Err()).To(Equal(testError))
+			})
+		})
+
+		Context("with WithMaxKeys", func() {
+			It("should forget the least recently seen values", func() {
+				// 1 is seen again before 3 arrives, so 2 is the one evicted
+				out := op.Distinct(op.FormSlice([]int{1, 2, 1, 3, 1, 2}), op.WithMaxKeys(2))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{1, 2, 3, 2}))
+			})
+		})
+	})
+
+	Describe("DistinctBy", func() {
+		type order struct {
+			ID    string
+			Items []string
+		}
+
+		Context("when values are not comparable", func() {
+			It("should deduplicate by the selected key", func() {
+				source := op.FormSlice([]order{
+					{ID: "a", Items: []string{"x"}},
+					{ID: "b"},
+					{ID: "a", Items: []string{"y"}},
+				})
+
+				results := make([]order, 0)
+				for result := range op.DistinctBy(source, func(o order) string { return o.ID }) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(HaveLen(2))
+				Expect(results[0].Items).To(Equal([]string{"x"}))
+				Expect(results[1].ID).To(Equal("b"))
+			})
+		})
+	})
+
+	Describe("DistinctUntilChanged", func() {
+		Context("when the source repeats values", func() {
+			It("should suppress consecutive duplicates only", func() {
+				results := make([]int, 0)
+				for result := range op.DistinctUntilChanged(op.FormSlice([]int{1, 1, 2, 2, 2, 1, 3, 3})) {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{1, 2, 1, 3}))
+			})
+		})
+
+		Context("when the source contains errors", func() {
+			It("should forward every error without deduplicating them", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 5)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.DistinctUntilChanged(source) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(4))
+				Expect(results[0].Unwrap()).To(Equal(1))
+				Expect(results[1].Err()).To(Equal(testError))
+				Expect(results[2].Err()).To(Equal(testError))
+				Expect(results[3].Unwrap()).To(Equal(2))
+			})
+		})
+	})
+
+	Describe("DistinctUntilChangedBy", func() {
+		type reading struct {
+			Status string
+			Tags   []string
+		}
+
+		Context("when values are not comparable", func() {
+			It("should compare the selected keys", func() {
+				source := op.FormSlice([]reading{
+					{Status: "up", Tags: []string{"a"}},
+					{Status: "up", Tags: []string{"b"}},
+					{Status: "down", Tags: nil},
+					{Status: "up", Tags: nil},
+				})
+
+				out := op.DistinctUntilChangedBy(source, func(r reading) string { return r.Status })
+
+				results := make([]reading, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(HaveLen(3))
+				Expect(results[0].Tags).To(Equal([]string{"a"}))
+				Expect(results[1].Status).To(Equal("down"))
+				Expect(results[2].Status).To(Equal("up"))
+			})
+		})
+	})
+
+	Describe("DistinctUntilChangedTTL", func() {
+		Context("when consecutive values repeat", func() {
+			It("should suppress duplicates until the ttl elapses", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				source := make(chan trx.Result[string])
+
+				out := op.DistinctUntilChangedTTL(source, time.Minute, op.WithClock(clock))
+
+				results := make(chan []string)
+				go func() {
+					values := make([]string, 0)
+					for result := range out {
+						values = append(values, result.Unwrap())
+					}
+					results <- values
+				}()
+
+				source <- trx.Ok("up")
+				source <- trx.Ok("up")
+				clock.Advance(30 * time.Second)
+				source <- trx.Ok("up")
+				clock.Advance(30 * time.Second)
+				source <- trx.Ok("up") // ttl elapsed, emitted again
+				source <- trx.Ok("down")
+				source <- trx.Ok("up")
+				close(source)
+
+				Expect(<-results).To(Equal([]string{"up", "up", "down", "up"}))
+			})
+		})
+
+		Context("when the source contains errors", func() {
+			It("should forward every error", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 4)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(1)
+				close(source)
+
+				out := op.DistinctUntilChangedTTL(source, time.Hour)
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(3))
+				Expect(results[1].Err()).To(Equal(testError))
+				Expect(results[2].Err()).To(Equal(testError))
+			})
+		})
+	})
+
+	Describe("EveryNth", func() {
+		Context("when downsampling by count", func() {
+			It("should emit the values at indices divisible by n", func() {
+				out := op.EveryNth(op.Range(0, 10), 3)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 3, 6, 9}))
+			})
+		})
+
+		Context("when the source contains errors", func() {
+			It("should forward errors without counting them", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 4)
+				source <- trx.Ok(0)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.EveryNth(source, 2) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(3))
+				Expect(results[0].Unwrap()).To(Equal(0))
+				Expect(results[1].Err()).To(Equal(testError))
+				Expect(results[2].Unwrap()).To(Equal(2))
+			})
+		})
+	})
+
+	Describe("DistinctWithinCount", func() {
+		Context("when values repeat around the window boundary", func() {
+			It("should suppress repeats inside the window and emit those outside", func() {
+				// With a window of 2: the second "a" is within the last 2 emissions (suppressed),
+				// the third "a" comes after "b" and "c" pushed it out (emitted).
+				source := op.FormSlice([]string{"a", "b", "a", "c", "a", "c"})
+				out := op.DistinctWithinCount(source, 2)
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"a", "b", "c", "a"}))
+			})
+		})
+
+		Context("when the source contains errors", func() {
+			It("should forward them", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(1)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.DistinctWithinCount(source, 5) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(2))
+				Expect(results[1].Err()).To(Equal(testError))
+			})
+		})
+	})
+
+	Describe("IgnoreElements", func() {
+		Context("when the source has only values", func() {
+			It("should close without emitting anything", func() {
+				results := make([]trx.Result[int], 0)
+				for result := range op.IgnoreElements(op.Range(0, 5)) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(BeEmpty())
+			})
+		})
+
+		Context("when the source contains errors", func() {
+			It("should forward only the errors", func() {
+				firstError := errors.New("first error")
+				secondError := errors.New("second error")
+				source := make(chan trx.Result[int], 4)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](firstError)
+				source <- trx.Ok(2)
+				source <- trx.Err[int](secondError)
+				close(source)
+
+				errs := make([]error, 0)
+				for result := range op.IgnoreElements(source) {
+					errs = append(errs, result.Err())
+				}
+
+				Expect(errs).To(Equal([]error{firstError, secondError}))
+			})
+		})
+	})
+
+	Describe("DropUntilOk", func() {
+		Context("when the source starts with errors", func() {
+			It("should forward only the results from the first Ok on", func() {
+				laterError := errors.New("later error")
+				source := make(chan trx.Result[int], 6)
+				source <- trx.Err[int](errors.New("startup error 1"))
+				source <- trx.Err[int](errors.New("startup error 2"))
+				source <- trx.Err[int](errors.New("startup error 3"))
+				source <- trx.Ok(1)
+				source <- trx.Err[int](laterError)
+				source <- trx.Ok(2)
+				close(source)
+
+				results := make([]trx.Result[int], 0)
+				for result := range op.DropUntilOk(source) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(3))
+				Expect(results[0].Unwrap()).To(Equal(1))
+				Expect(results[1].Err()).To(Equal(laterError))
+				Expect(results[2].Unwrap()).To(Equal(2))
+			})
+		})
 	})
 
 	Describe("Combined filtering operations", func() {
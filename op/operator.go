@@ -2,10 +2,20 @@
 // It offers a simple, configurable way to create typed channels with optional buffering,
 // specifically designed to work with trx.Result types for type-safe error handling
 // in concurrent operations.
+//
+// Every operator follows the same shutdown contract: once it closes its output channel, all goroutines
+// it started internally have exited. The only exception is a goroutine that drains a source channel on the
+// caller's behalf after a cancellation or an early exit (see FormChannel, ToIterator and Take); it exits as
+// soon as that source is closed. User-supplied goroutines, such as the producers behind inner channels
+// returned by callbacks, are not covered by this contract. trxtest.AssertNoLeak can be used to verify it
+// for a whole pipeline.
 package op
 
 import (
 	"context"
+	"errors"
+	"math"
+	"time"
 
 	"github.com/foreveralonet/trx"
 )
@@ -13,10 +23,58 @@ import (
 // config holds configuration options for channel creation.
 // This struct is used internally to store settings provided through functional options.
 type config struct {
-	bufferSize int  // Size of the channel buffer (0 = unbuffered)
-	poolSize   int  // Number of worker goroutines in the pool (must be > 0)
-	serialize  bool // Serialize output when poolSize >= 1
-	ctx        context.Context
+	bufferSize  int  // Size of the channel buffer (0 = unbuffered)
+	poolSize    int  // Number of worker goroutines in the pool (must be > 0)
+	poolSizeSet bool // The pool size was set with WithPoolSize
+	serialize   bool // Serialize output when poolSize >= 1
+	coalesce    bool // Collapse missed ticks into the latest one
+	align       bool // Align time windows to wall-clock boundaries
+	trailing    bool // Also emit the last value seen during a throttle window
+	flush       bool // Emit the last unsampled value when the source completes
+	limit       int  // Maximum number of emissions (0 = unlimited)
+	total       int  // Expected number of source values (0 = unknown)
+	maxKeys     int  // Maximum number of remembered keys (0 = unlimited)
+	dropOnFull  bool // Drop results instead of blocking when the output channel is full
+	onDrop      func()
+	cancelErr   bool // Emit an error result when the context is cancelled
+	copyBatches bool // Emit defensive copies of batches
+	sourceBuf   int  // Size of the internal buffer between source reading and processing (0 = none)
+	contiguous  bool // Only advance the index for successful values
+	onStart     func()
+	onStop      func(reason StopReason)
+	name        string // Name of the operator stage
+	wrapErrors  bool   // Prefix forwarded errors with the stage name
+	pool        Pool   // User-provided worker pool (nil = built-in)
+	ownPool     bool   // The pool belongs to this operator alone, so tasks may emit on its workers
+	reorder     int    // Size of the reorder window for ordered concurrency (0 = disabled)
+	retries     int    // Number of retries of a failed item (0 = none)
+	retryDelay  time.Duration
+	clock       Clock
+	upstream    context.CancelFunc
+	ctx         context.Context
+}
+
+// StopReason describes why an operator stopped, as reported to the WithOnStop hook.
+type StopReason int
+
+const (
+	StopCompleted StopReason = iota // The source was exhausted, or the operator finished early on purpose
+	StopCancelled                   // The context was cancelled
+	StopErrored                     // The operator completed after emitting at least one error
+)
+
+// String returns a human-readable name of the reason.
+func (r StopReason) String() string {
+	switch r {
+	case StopCompleted:
+		return "completed"
+	case StopCancelled:
+		return "cancelled"
+	case StopErrored:
+		return "errored"
+	default:
+		return "unknown"
+	}
 }
 
 // Option represents an option for the channel utility.
@@ -53,6 +111,77 @@ func WithPoolSize(size int) Option {
 	return func(c *config) {
 		if size > 0 {
 			c.poolSize = size
+			c.poolSizeSet = true
+		}
+	}
+}
+
+// WithPool returns an Option that makes operators such as `Map` and `Filter` run their tasks on the given
+// pool instead of creating their own. Sharing one pool across the stages of a pipeline caps the total number
+// of worker goroutines, and lets advanced users plug in their own pool implementation. Operators only wait
+// for the tasks they submitted and never call Wait. Results are emitted in completion order, unless
+// WithSerialize or WithOrderedConcurrency is set, in which case they are emitted in source order. A nil pool
+// is ignored.
+//
+// Only the user function runs on the pool: results are emitted off the pool's workers, so a stage whose
+// consumer is slow never holds workers that other stages sharing the pool need. By default the number of
+// tasks of the operator in flight is only bounded by the pool itself, and results waiting for a slow consumer
+// are not bounded at all. WithPoolSize caps how many tasks are in flight at once, including those whose result
+// is still waiting to be emitted, and WithOrderedConcurrency sets that cap when results are emitted in order.
+//
+// Example:
+//
+//	shared := newWorkerPool(16) // Any Pool implementation
+//	out := Filter(Map(source, fetch, WithPool(shared), WithPoolSize(8)), check, WithPool(shared), WithPoolSize(8))
+func WithPool(p Pool) Option {
+	return func(c *config) {
+		if p != nil {
+			c.pool = p
+		}
+	}
+}
+
+// withOwnPool returns an Option that runs the operator's tasks on p, a pool created for this operator alone.
+// Unlike WithPool, tasks emit on the pool's workers and the pool's size is the only bound on them.
+func withOwnPool(p Pool) Option {
+	return func(c *config) {
+		c.pool = p
+		c.ownPool = true
+	}
+}
+
+// WithOrderedConcurrency returns an Option that makes operators such as `Map` and `Filter` process up to
+// maxReorder items in parallel while still emitting results in source order. Finished results wait in a
+// reorder buffer until all earlier ones are emitted; the source is not read further ahead than maxReorder
+// items past the oldest pending one, so a slow item bounds memory instead of letting the buffer grow.
+// WithPoolSize and WithSerialize have no effect. With WithPool, the tasks run on that pool. Values less than
+// or equal to 0 are ignored.
+//
+// Example:
+//
+//	out := Map(ids, fetch, WithOrderedConcurrency(16)) // 16 fetches in flight, results in order
+func WithOrderedConcurrency(maxReorder int) Option {
+	return func(c *config) {
+		if maxReorder > 0 {
+			c.reorder = maxReorder
+		}
+	}
+}
+
+// WithItemRetry returns an Option that makes `Map` and `MapFilter` retry an item whose mapper returned an
+// error, up to attempts more times with delay between attempts, before the last error is forwarded. This
+// handles flaky per-item operations such as network calls without re-running the whole stream. The wait
+// between attempts ends early when the context is cancelled. ErrStop is never retried. Non-positive attempts
+// are ignored and failed items are not retried (default).
+//
+// Example:
+//
+//	out := Map(urls, fetch, WithItemRetry(3, 100*time.Millisecond))
+func WithItemRetry(attempts int, delay time.Duration) Option {
+	return func(c *config) {
+		if attempts > 0 {
+			c.retries = attempts
+			c.retryDelay = delay
 		}
 	}
 }
@@ -68,8 +197,274 @@ func WithSerialize() Option {
 	}
 }
 
+// WithCoalesce returns an Option that makes time-based creation operators such as `Interval` collapse
+// missed ticks when the consumer falls behind. The tick counter keeps advancing, but only the latest
+// pending tick is delivered instead of a burst of stale ones.
+//
+// Example:
+//
+//	Interval(time.Second, WithCoalesce()) // Slow consumers only see the latest tick
+func WithCoalesce() Option {
+	return func(c *config) {
+		c.coalesce = true
+	}
+}
+
+// WithAlignToClock returns an Option that aligns the windows of `BufferWithTime` to clean wall-clock
+// boundaries that are multiples of the window duration (e.g. every minute on the minute) instead of
+// starting them when the stream begins. The first window may therefore be shorter than the duration.
+//
+// Example:
+//
+//	BufferWithTime(source, time.Minute, 0, WithAlignToClock()) // Flushes at hh:mm:00
+func WithAlignToClock() Option {
+	return func(c *config) {
+		c.align = true
+	}
+}
+
+// WithTrailing returns an Option that makes `ThrottleTime` also emit the last value it ignored during a
+// throttle window once the window ends, so the final state of a burst is never lost.
+//
+// Example:
+//
+//	ThrottleTime(positions, 100*time.Millisecond, WithTrailing())
+func WithTrailing() Option {
+	return func(c *config) {
+		c.trailing = true
+	}
+}
+
+// WithFlushOnComplete returns an Option that makes sampling operators such as `SampleTime` emit the last
+// value that has not been sampled yet when the source completes, instead of dropping it.
+//
+// Example:
+//
+//	SampleTime(prices, time.Second, WithFlushOnComplete())
+func WithFlushOnComplete() Option {
+	return func(c *config) {
+		c.flush = true
+	}
+}
+
+// WithClock returns an Option that sets the Clock used by time-based operators to read the current time.
+// It is mainly useful in tests to control time deterministically. A nil clock is ignored.
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithLimit returns an Option that caps how many results an operator such as `Map` or `Filter` emits.
+// Once the limit is reached, the operator stops reading its source and closes its output channel.
+// Values less than or equal to 0 are ignored and the output is unlimited (default).
+//
+// Example:
+//
+//	WithLimit(10) // Emit at most 10 results
+func WithLimit(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.limit = n
+		}
+	}
+}
+
+// WithTotalHint returns an Option that tells operators such as `TakePercent` how many values the source
+// will produce, so they can stream instead of buffering the whole source to count it first.
+// Values less than or equal to 0 are ignored and the total is unknown (default).
+//
+// Example:
+//
+//	WithTotalHint(len(records)) // The source emits one value per record
+func WithTotalHint(total int) Option {
+	return func(c *config) {
+		if total > 0 {
+			c.total = total
+		}
+	}
+}
+
+// WithMaxKeys returns an Option that caps how many keys operators such as `Distinct` remember. When the
+// cap is reached, the least recently seen key is forgotten to make room for a new one, so memory stays bounded
+// at the cost of possibly emitting a forgotten value again. Values less than or equal to 0 are ignored and
+// every key is remembered (default).
+//
+// Example:
+//
+//	WithMaxKeys(10_000) // Remember at most 10,000 keys
+func WithMaxKeys(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.maxKeys = n
+		}
+	}
+}
+
+// WithDropOnBackpressure returns an Option that makes operators such as `Map` and `Filter` drop a result
+// instead of blocking when the output channel is full. This suits lossy real-time pipelines where stale
+// values are worthless (e.g. UI frame updates). Combine it with WithBufferSize, since an unbuffered output
+// drops every result that the consumer is not already waiting for. Only successful values are dropped:
+// errors are never lost, and their emission waits for the consumer like without this option.
+//
+// Example:
+//
+//	WithDropOnBackpressure() // Never block workers on a slow consumer
+func WithDropOnBackpressure() Option {
+	return func(c *config) {
+		c.dropOnFull = true
+	}
+}
+
+// WithOnDrop returns an Option that registers a function called each time a value is dropped because of
+// WithDropOnBackpressure. It can be used to count dropped results. The function may be called concurrently.
+//
+// Example:
+//
+//	var dropped atomic.Int64
+//	WithOnDrop(func() { dropped.Add(1) })
+func WithOnDrop(fn func()) Option {
+	return func(c *config) {
+		c.onDrop = fn
+	}
+}
+
+// WithUpstreamCancel returns an Option that gives an operator the cancel function of the context its
+// upstream operators run with. Operators that stop reading their source before it is exhausted, such as
+// `Take`, call cancel and drain the rest of the source in the background, so the upstream goroutines exit
+// instead of staying blocked on a send. A nil cancel function is ignored.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(parent)
+//	out := Take(Interval(time.Second, WithContext(ctx)), 5, WithUpstreamCancel(cancel))
+func WithUpstreamCancel(cancel context.CancelFunc) Option {
+	return func(c *config) {
+		if cancel != nil {
+			c.upstream = cancel
+		}
+	}
+}
+
+// WithEmitCancellationError returns an Option that makes operators such as `FormChannel`, `Map` and `Filter`
+// emit the cancellation cause as a final trx.Err result when they stop because their context was cancelled.
+// The cause is the one given to the cancel function of context.WithCancelCause, or the context error, such as
+// context.Canceled, when none was given (see context.Cause). Without it, cancellation simply closes the
+// output channel, which is indistinguishable from the source completing normally.
+//
+// The final send is best-effort: if the consumer does not receive the cause within a short grace period, for
+// example because it stopped reading before cancelling the context, the cause is dropped and the output
+// channel is closed, so the operator never stays blocked.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancelCause(parent)
+//	out := Map(source, mapper, WithContext(ctx), WithEmitCancellationError())
+//	cancel(ErrShuttingDown) // out emits ErrShuttingDown, then closes
+func WithEmitCancellationError() Option {
+	return func(c *config) {
+		c.cancelErr = true
+	}
+}
+
+// WithCopyBatches returns an Option that makes batching operators such as `BufferWithCount` emit a
+// defensive copy of each batch, so a consumer can freely modify or append to a received slice without
+// sharing memory with the operator.
+//
+// Example:
+//
+//	BufferWithCount(source, 10, WithCopyBatches())
+func WithCopyBatches() Option {
+	return func(c *config) {
+		c.copyBatches = true
+	}
+}
+
+// WithContiguousIndex returns an Option that makes `Map` and `Filter` pass indices that count only the
+// successful values reaching the mapper or predicate at this stage, so they are always 0-based and
+// contiguous. By default the index advances for every result read from the source, including errors,
+// which leaves gaps when the source carries errors.
+//
+// Example:
+//
+//	Map(Filter(source, isValid), mapper, WithContiguousIndex())
+func WithContiguousIndex() Option {
+	return func(c *config) {
+		c.contiguous = true
+	}
+}
+
+// WithName returns an Option that names an operator stage, which makes it identifiable in a multi-stage
+// pipeline. The name is used by WithWrapErrors.
+//
+// Example:
+//
+//	Map(source, parse, WithName("parse"))
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithWrapErrors returns an Option that makes `Map` and `Filter` wrap every error they forward, whether it
+// comes from the source or from the user function, with the stage name set by WithName, as in
+// "parse: invalid syntax". The original error is wrapped with %w, so errors.Is and errors.As still match it.
+// Errors are left unchanged when no name is set.
+//
+// Example:
+//
+//	Map(source, parse, WithName("parse"), WithWrapErrors())
+func WithWrapErrors() Option {
+	return func(c *config) {
+		c.wrapErrors = true
+	}
+}
+
+// WithSourceBuffer returns an Option that inserts an internal buffered stage of size n between reading
+// the source and submitting work to the pool in operators such as `Map` and `Filter`. The source keeps
+// being read while all workers are busy, which smooths out bursty sources. Values less than or equal
+// to 0 are ignored (default: no internal buffer).
+//
+// Example:
+//
+//	Map(source, mapper, WithPoolSize(4), WithSourceBuffer(64))
+func WithSourceBuffer(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.sourceBuf = n
+		}
+	}
+}
+
+// WithOnStart returns an Option that registers a function called when the goroutine of an operator such as
+// `Map` or `Filter` starts. It is useful for logging pipeline startup.
+//
+// Example:
+//
+//	WithOnStart(func() { log.Println("map started") })
+func WithOnStart(fn func()) Option {
+	return func(c *config) {
+		c.onStart = fn
+	}
+}
+
+// WithOnStop returns an Option that registers a function called when an operator such as `Map` or `Filter`
+// shuts down, after its output channel has been closed, with the reason it stopped.
+//
+// Example:
+//
+//	WithOnStop(func(reason StopReason) { log.Println("map stopped:", reason) })
+func WithOnStop(fn func(reason StopReason)) Option {
+	return func(c *config) {
+		c.onStop = fn
+	}
+}
+
 // WithContext returns an Option that sets the provided context on the operator's configuration.
-// When the given context is canceled, any ongoing operation such as `Map` will be stopped (without error).
+// When the given context is canceled, any ongoing operation such as `Map` or a creation operator such as
+// `Range` will be stopped (without error).
 func WithContext(ctx context.Context) Option {
 	return func(c *config) {
 		c.ctx = ctx
@@ -81,6 +476,7 @@ func defaultConfig() *config {
 		bufferSize: 0,
 		poolSize:   1, // Default pool size is 1
 		serialize:  false,
+		clock:      realClock{},
 	}
 }
 
@@ -94,14 +490,141 @@ func parseOption(opts ...Option) *config {
 	return c
 }
 
+func (c *config) started() {
+	if c.onStart != nil {
+		c.onStart()
+	}
+}
+
+func (c *config) stopped(reason StopReason) {
+	if c.onStop != nil {
+		c.onStop(reason)
+	}
+}
+
 func makeResultChannel[T any](c *config) chan trx.Result[T] {
 	return make(chan trx.Result[T], c.bufferSize)
 }
 
+// makeSourceBuffer returns source unchanged, or a buffered channel fed by a relay goroutine when
+// WithSourceBuffer is set. The relay stops when source is closed, the context is cancelled, or done is closed.
+func makeSourceBuffer[T any](c *config, ctx context.Context, done <-chan struct{}, source <-chan trx.Result[T]) <-chan trx.Result[T] {
+	if c.sourceBuf <= 0 {
+		return source
+	}
+
+	buffered := make(chan trx.Result[T], c.sourceBuf)
+
+	go func() {
+		defer close(buffered)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-done:
+					return
+				case buffered <- v:
+				}
+			}
+		}
+	}()
+
+	return buffered
+}
+
 func makePool(c *config) *pool {
+	if c.ownPool {
+		return &pool{custom: c.pool}
+	}
+
+	if c.pool != nil {
+		bound := math.MaxInt
+		if c.poolSizeSet {
+			bound = c.poolSize
+		}
+
+		if c.reorder > 0 {
+			return &pool{ordered: newOrderedPool(c.reorder, c.pool)}
+		}
+
+		if c.serialize {
+			return &pool{ordered: newOrderedPool(bound, c.pool)}
+		}
+
+		p := &pool{ctx: makeContext(c), custom: c.pool, offWorker: true}
+		if c.poolSizeSet {
+			p.slots = make(chan struct{}, bound)
+		}
+
+		return p
+	}
+
+	if c.reorder > 0 {
+		return &pool{ordered: newOrderedPool(c.reorder, nil)}
+	}
+
 	return newPool(c.poolSize, c.serialize)
 }
 
+// retryItem calls fn and, while it fails, calls it again up to the number of times configured with
+// WithItemRetry, waiting between attempts unless ctx is cancelled. It returns the outcome of the last call.
+func retryItem[U any](ctx context.Context, c *config, fn func() (U, error)) (U, error) {
+	v, err := fn()
+	for attempt := 0; attempt < c.retries && err != nil && !errors.Is(err, ErrStop); attempt++ {
+		select {
+		case <-ctx.Done():
+			return v, err
+		case <-c.clock.After(c.retryDelay):
+		}
+
+		v, err = fn()
+	}
+
+	return v, err
+}
+
+// cancellationGrace is how long an operator waits for the consumer to receive the cancellation cause
+// emitted with WithEmitCancellationError before giving up.
+const cancellationGrace = 100 * time.Millisecond
+
+// emitCancellation sends the cancellation cause of ctx downstream when WithEmitCancellationError is set.
+// It gives up after cancellationGrace, so a consumer that stopped reading does not block the operator.
+func emitCancellation[T any](c *config, ctx context.Context, out chan<- trx.Result[T]) {
+	if !c.cancelErr {
+		return
+	}
+
+	timer := time.NewTimer(cancellationGrace)
+	defer timer.Stop()
+
+	select {
+	case out <- trx.Err[T](context.Cause(ctx)):
+	case <-timer.C:
+	}
+}
+
+// releaseUpstream lets the upstream of an operator that stops reading source early shut down, when
+// WithUpstreamCancel is set: it cancels the upstream context and drains source until it is closed.
+func releaseUpstream[T any](c *config, source <-chan trx.Result[T]) {
+	if c.upstream == nil {
+		return
+	}
+
+	c.upstream()
+	go drain(source)
+}
+
 func makeContext(c *config) context.Context {
 	if c.ctx != nil {
 		return c.ctx
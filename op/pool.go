@@ -1,18 +1,83 @@
 package op
 
 import (
+	"context"
+	"sync"
+
 	basePool "github.com/sourcegraph/conc/pool"
 	"github.com/sourcegraph/conc/stream"
 )
 
+// Pool is a worker pool that operators such as Map and Filter can run their tasks on instead of their
+// built-in pool (see WithPool). Implementations must be safe for concurrent use.
+type Pool interface {
+	// Submit runs task on a worker, blocking until one is available if the pool is at capacity.
+	Submit(task func())
+	// Wait blocks until all submitted tasks have finished.
+	Wait()
+}
+
 type pool struct {
-	pool   *basePool.Pool
-	stream *stream.Stream
+	ctx       context.Context
+	pool      *basePool.Pool
+	stream    *stream.Stream
+	custom    Pool
+	offWorker bool           // Emit off the workers of custom, which may be shared with other operators
+	tasks     sync.WaitGroup // Tasks submitted to custom
+	slots     chan struct{}  // Bounds the tasks submitted to custom that have not emitted yet (nil = unbounded)
+	ordered   *orderedPool
 }
 
 type callback = func()
 
 func (p *pool) submit(fn func() callback) {
+	if p.ordered != nil {
+		p.ordered.submit(fn)
+
+		return
+	}
+
+	if p.custom != nil && !p.offWorker {
+		p.tasks.Add(1)
+		p.custom.Submit(func() {
+			defer p.tasks.Done()
+
+			cb := fn()
+			cb()
+		})
+
+		return
+	}
+
+	if p.custom != nil {
+		if p.slots != nil {
+			select {
+			case <-p.ctx.Done():
+				// The operator is shutting down, so the task would only be discarded.
+				return
+			case p.slots <- struct{}{}:
+			}
+		}
+
+		p.tasks.Add(1)
+		p.custom.Submit(func() {
+			cb := fn()
+
+			// Emit off the pool's worker: a task blocked on a full output channel must not hold a worker
+			// that another stage sharing the pool needs in order to read that output.
+			go func() {
+				defer p.tasks.Done()
+				if p.slots != nil {
+					defer func() { <-p.slots }()
+				}
+
+				cb()
+			}()
+		})
+
+		return
+	}
+
 	if p.pool != nil {
 		p.pool.Go(func() {
 			cb := fn()
@@ -37,6 +102,19 @@ func (p *pool) submit(fn func() callback) {
 }
 
 func (p *pool) wait() {
+	if p.ordered != nil {
+		p.ordered.wait()
+
+		return
+	}
+
+	if p.custom != nil {
+		// Only wait for our own tasks, since a custom pool may be shared with other operators.
+		p.tasks.Wait()
+
+		return
+	}
+
 	if p.pool != nil {
 		p.pool.Wait()
 
@@ -65,3 +143,146 @@ func newPool(size int, serialize bool) *pool {
 		stream: stream.New().WithMaxGoroutines(size),
 	}
 }
+
+// resizablePool runs tasks on at most size goroutines at once, where size can be changed at any time.
+// Shrinking never interrupts running tasks: new tasks simply wait until enough of them have finished.
+type resizablePool struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	size    int
+	running int
+	wg      sync.WaitGroup
+}
+
+func newResizablePool(size int) *resizablePool {
+	p := &resizablePool{size: max(size, 1)}
+	p.cond = sync.NewCond(&p.mu)
+
+	return p
+}
+
+// Submit runs fn on a new goroutine, blocking until the number of running tasks is below the pool size.
+func (p *resizablePool) Submit(fn func()) {
+	p.mu.Lock()
+	for p.running >= p.size {
+		p.cond.Wait()
+	}
+	p.running++
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() {
+			p.mu.Lock()
+			p.running--
+			p.cond.Broadcast()
+			p.mu.Unlock()
+		}()
+
+		fn()
+	}()
+}
+
+// Wait blocks until all submitted tasks have finished.
+func (p *resizablePool) Wait() {
+	p.wg.Wait()
+}
+
+// Resize changes the maximum number of concurrently running tasks. Sizes below 1 are treated as 1.
+func (p *resizablePool) Resize(size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.size = max(size, 1)
+	p.cond.Broadcast()
+}
+
+// orderedPool runs every task on its own goroutine, or on custom when set, and runs their callbacks in
+// submission order, using a reorder buffer keyed by sequence number. At most window tasks are in flight past
+// the next callback to run, which bounds both the parallelism and the number of buffered callbacks; submit
+// blocks while it is full. Callbacks never run on the workers of custom, which may be shared.
+type orderedPool struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	window int
+	custom Pool
+	next   int // Sequence number of the next submitted task
+	cursor int // Sequence number of the next callback to run
+	ready  map[int]callback
+	wg     sync.WaitGroup
+}
+
+func newOrderedPool(window int, custom Pool) *orderedPool {
+	p := &orderedPool{
+		window: max(window, 1),
+		custom: custom,
+		ready:  make(map[int]callback),
+	}
+	p.cond = sync.NewCond(&p.mu)
+
+	return p
+}
+
+func (p *orderedPool) submit(fn func() callback) {
+	p.mu.Lock()
+	for p.next-p.cursor >= p.window {
+		p.cond.Wait()
+	}
+	seq := p.next
+	p.next++
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	if p.custom == nil {
+		go func() {
+			defer p.wg.Done()
+
+			p.complete(seq, fn())
+		}()
+
+		return
+	}
+
+	p.custom.Submit(func() {
+		cb := fn()
+
+		go func() {
+			defer p.wg.Done()
+
+			p.complete(seq, cb)
+		}()
+	})
+}
+
+// complete records the callback of task seq and, if it is the next one in order, runs it along with the
+// callbacks of the following tasks that are already done.
+func (p *orderedPool) complete(seq int, cb callback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.ready[seq] = cb
+	if seq != p.cursor {
+		// An earlier task is still running or flushing; it will run this callback in turn.
+		return
+	}
+
+	for {
+		next, ok := p.ready[p.cursor]
+		if !ok {
+			return
+		}
+		delete(p.ready, p.cursor)
+
+		p.mu.Unlock()
+		next()
+		p.mu.Lock()
+
+		p.cursor++
+		p.cond.Broadcast()
+	}
+}
+
+func (p *orderedPool) wait() {
+	p.wg.Wait()
+}
@@ -1,6 +1,11 @@
 package op
 
 import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/foreveralonet/trx"
@@ -10,10 +15,14 @@ import (
 // emitting the results to a new output channel. The mapper function receives the value and its
 // index in the sequence, and may return an error. If an error occurs during mapping or when
 // retrieving the value from the source, the error is sent downstream wrapped in a trx.Result.
+// The index counts every result read from the source, including errors; WithContiguousIndex makes it
+// count only the values passed to the mapper. The mapper can return ErrStop to end the stream early:
+// results of earlier items are still emitted, then the output channel is closed without emitting an error.
 //
 // The function supports optional configuration via Option parameters, such as context control
 // and concurrency settings. Mapping operations are performed concurrently using a worker pool,
-// and the output channel is closed once all mapping operations are complete.
+// and the output channel is closed once all mapping operations are complete. A consumer that stops
+// reading early should cancel the context: workers blocked on emission then give up and the operator exits.
 //
 // Type Parameters:
 //
@@ -28,6 +37,19 @@ import (
 //	    - WithBufferSize
 //	    - WithPoolSize
 //	    - WithSerialize
+//	    - WithLimit
+//	    - WithDropOnBackpressure
+//	    - WithOnDrop
+//	    - WithPool
+//	    - WithItemRetry
+//	    - WithOrderedConcurrency
+//	    - WithSourceBuffer
+//	    - WithContiguousIndex
+//	    - WithName
+//	    - WithWrapErrors
+//	    - WithOnStart
+//	    - WithOnStop
+//	    - WithEmitCancellationError
 //	    - WithContext
 //
 // Returns:
@@ -40,92 +62,1750 @@ import (
 //	    return strconv.Itoa(v), nil
 //	})
 func Map[T, U any](source <-chan trx.Result[T], mapper func(value T, index int) (U, error), options ...Option) <-chan trx.Result[U] {
+	return mapAndFilter(source, func(value T, index int) (U, bool, error) {
+		mapped, err := mapper(value, index)
+
+		return mapped, true, err
+	}, options...)
+}
+
+// MapOk applies the provided function to each successful value received from the source channel.
+// It is a convenience wrapper around Map for pure transformations that never fail and do not need
+// the index. Errors received from the source are still sent downstream wrapped in a trx.Result.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	fn     - A function that maps each value to a new value of type U.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithLimit
+//	    - WithDropOnBackpressure
+//	    - WithOnDrop
+//	    - WithSourceBuffer
+//	    - WithOnStart
+//	    - WithOnStop
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the mapped results or errors.
+//
+// Example usage:
+//
+//	out := MapOk(source, strconv.Itoa)
+func MapOk[T, U any](source <-chan trx.Result[T], fn func(value T) U, options ...Option) <-chan trx.Result[U] {
+	return Map(source, func(value T, _ int) (U, error) {
+		return fn(value), nil
+	}, options...)
+}
+
+// MapResult applies the provided mapper function to each item received from the source channel, like Map,
+// but the mapper returns a trx.Result[U] directly instead of a (U, error) pair. This composes naturally
+// with APIs that already produce a Result, such as trx.Try.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	mapper - A function that maps each value and its index to a trx.Result[U].
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the mapped results or errors.
+//
+// Example usage:
+//
+//	out := MapResult(source, func(s string, _ int) trx.Result[int] {
+//	    return trx.Try(func() (int, error) { return strconv.Atoi(s) })
+//	})
+func MapResult[T, U any](source <-chan trx.Result[T], mapper func(value T, index int) trx.Result[U], options ...Option) <-chan trx.Result[U] {
+	return Map(source, func(value T, index int) (U, error) {
+		result := mapper(value, index)
+
+		return result.Get()
+	}, options...)
+}
+
+// MapValidated applies the provided mapper function to each item received from the source channel, like Map,
+// then runs validate on each mapped value. If validate returns an error, that error is sent downstream
+// wrapped in a trx.Result instead of the value. This centralizes post-mapping checks without a separate
+// Filter or Map stage.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source   - A receive-only channel of trx.Result[T] representing the input stream.
+//	mapper   - A function that maps each value and its index to a new value of type U, possibly returning an error.
+//	validate - A function that checks each mapped value and returns an error to reject it.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the validated results or errors.
+//
+// Example usage:
+//
+//	out := MapValidated(source, parseOrder, func(o Order) error {
+//	    if o.Quantity <= 0 {
+//	        return ErrInvalidQuantity
+//	    }
+//	    return nil
+//	})
+func MapValidated[T, U any](source <-chan trx.Result[T], mapper func(value T, index int) (U, error), validate func(value U) error, options ...Option) <-chan trx.Result[U] {
+	return Map(source, func(value T, index int) (U, error) {
+		mapped, err := mapper(value, index)
+		if err != nil {
+			return mapped, err
+		}
+
+		if err := validate(mapped); err != nil {
+			var zero U
+
+			return zero, err
+		}
+
+		return mapped, nil
+	}, options...)
+}
+
+// MapFilter maps and filters in a single step: the mapper receives each value and its index and returns the
+// mapped value, whether to keep it, and an error. A kept value is emitted, a value that is not kept is
+// skipped without emitting anything, and a non-nil error is sent downstream wrapped in a trx.Result. This
+// saves a separate Filter stage when the decision to keep a value is only known while mapping it. Like Map,
+// the mapper can return ErrStop to end the stream early, and the index counts every result read from the
+// source unless WithContiguousIndex is set.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	mapper - A function that maps each value and its index to a value of type U and reports whether to keep it.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithLimit
+//	    - WithDropOnBackpressure
+//	    - WithOnDrop
+//	    - WithPool
+//	    - WithItemRetry
+//	    - WithOrderedConcurrency
+//	    - WithSourceBuffer
+//	    - WithContiguousIndex
+//	    - WithName
+//	    - WithWrapErrors
+//	    - WithOnStart
+//	    - WithOnStop
+//	    - WithEmitCancellationError
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the kept mapped values or errors.
+//
+// Example usage:
+//
+//	out := MapFilter(lines, func(line string, _ int) (int, bool, error) {
+//	    if strings.HasPrefix(line, "#") {
+//	        return 0, false, nil // Skip comments
+//	    }
+//	    n, err := strconv.Atoi(line)
+//	    return n, true, err
+//	})
+func MapFilter[T, U any](source <-chan trx.Result[T], mapper func(value T, index int) (U, bool, error), options ...Option) <-chan trx.Result[U] {
+	return mapAndFilter(source, mapper, options...)
+}
+
+// kept is a mapped value paired with whether it should be emitted.
+type kept[U any] struct {
+	value U
+	keep  bool
+}
+
+// mapAndFilter implements Map and MapFilter: it maps each value with mapper and emits the mapped value
+// only when mapper reports that it should be kept.
+func mapAndFilter[T, U any](source <-chan trx.Result[T], mapper func(value T, index int) (U, bool, error), options ...Option) <-chan trx.Result[U] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[U](conf)
+	pool := makePool(conf)
+	emitter := newEmitter(conf, out)
+	source = makeSourceBuffer(conf, ctx, emitter.done(), source)
+
+	go func() {
+		reason := StopCompleted
+		defer func() { conf.stopped(reason) }()
+		defer close(out)
+
+		conf.started()
+
+		i := 0
+		read := 0 // Position in the source, which unlike i always counts errors
+	LOOP:
+		for !emitter.isDone() {
+			select {
+			case <-ctx.Done():
+				reason = StopCancelled
+
+				break LOOP
+			case <-emitter.done():
+				break LOOP
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				index := i
+				position := read
+				result := v
+				read++
+
+				pool.submit(func() callback {
+					value, err := result.Get()
+					if err != nil {
+						return func() {
+							emitter.emitAt(position, trx.Err[U](err))
+						}
+					}
+
+					outcome, err := retryItem(ctx, conf, func() (kept[U], error) {
+						mapped, keep, err := mapper(value, index)
+
+						return kept[U]{value: mapped, keep: keep}, err
+					})
+					if errors.Is(err, ErrStop) {
+						return func() {
+							emitter.stop(position)
+						}
+					}
+
+					if err != nil {
+						return func() {
+							emitter.emitAt(position, trx.Err[U](err))
+						}
+					}
+
+					if outcome.keep {
+						return func() {
+							emitter.emitAt(position, trx.Ok(outcome.value))
+						}
+					}
+
+					return func() {}
+				})
+
+				if !conf.contiguous || result.IsOk() {
+					i++
+				}
+			}
+		}
+
+		// Workers blocked on emission are released by the cancellation, so waiting is safe.
+		pool.wait()
+
+		if reason == StopCancelled {
+			emitCancellation(conf, ctx, out)
+		}
+
+		if reason != StopCancelled && emitter.hasErrored() {
+			reason = StopErrored
+		}
+	}()
+
+	return out
+}
+
+// TryMap applies the provided function to each successful value received from the source channel,
+// recovering from panics. If fn panics for a value, the panic is converted into a trx.Err result for that
+// item (see trx.Try) and processing continues with the next value. This is a convenient bridge for
+// wrapping legacy transformations that panic instead of returning errors.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	fn     - A function that maps each value to a new value of type U, possibly panicking.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the mapped results or errors.
+//
+// Example usage:
+//
+//	out := TryMap(source, legacy.MustParse)
+func TryMap[T, U any](source <-chan trx.Result[T], fn func(value T) U, options ...Option) <-chan trx.Result[U] {
+	return Map(source, func(value T, _ int) (U, error) {
+		result := trx.Try(func() (U, error) {
+			return fn(value), nil
+		})
+
+		return result.Get()
+	}, options...)
+}
+
+// MapAsync maps each value received from the source channel to a future: a channel that yields a single
+// result, for example one produced by an asynchronous call. Each worker waits for its future to resolve and
+// emits that result. The number of futures awaited concurrently is bounded by WithPoolSize, and WithSerialize
+// preserves the source order regardless of the order in which the futures resolve. Only the first result of
+// each future is used; a future that closes without a value yields ErrNoElements.
+//
+// Unlike FlatMap, which relays every value of an inner channel, MapAsync models the one-result-per-item
+// future pattern.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	mapper - A function that starts the asynchronous work for a value and its index and returns its future.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the resolved results or errors.
+//
+// Example usage:
+//
+//	out := MapAsync(urls, func(url string, _ int) <-chan trx.Result[Page] {
+//	    return fetchAsync(url)
+//	}, WithPoolSize(8), WithSerialize())
+func MapAsync[T, U any](source <-chan trx.Result[T], mapper func(value T, index int) <-chan trx.Result[U], options ...Option) <-chan trx.Result[U] {
+	ctx := makeContext(parseOption(options...))
+
+	return Map(source, func(value T, index int) (U, error) {
+		select {
+		case <-ctx.Done():
+			var zero U
+
+			return zero, ctx.Err()
+		case r, ok := <-mapper(value, index):
+			if !ok {
+				var zero U
+
+				return zero, ErrNoElements
+			}
+
+			return r.Get()
+		}
+	}, options...)
+}
+
+// FlatMap maps each value received from the source channel to an inner channel and merges the results of
+// all inner channels into a single output channel, in the order they arrive. This supports fan-out
+// workflows such as streaming the records of every URL received from the source. The number of inner
+// channels consumed concurrently is bounded by WithPoolSize, which defaults to 1 and therefore concatenates
+// the inner channels one after another. Errors from the source and from the inner channels are forwarded
+// downstream. Inner channels are isolated from each other: an error from one inner channel is forwarded like
+// any other result and stops neither the source nor the other inner channels, which keep being flattened.
+// The output channel is closed once the source and all inner channels are closed.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of values from the inner channels.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	project - A function that returns the inner channel for a value and its index.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the values of all inner channels or errors.
+//
+// Example usage:
+//
+//	out := FlatMap(urls, func(url string, _ int) <-chan trx.Result[Record] {
+//	    return streamRecords(url)
+//	}, WithPoolSize(4))
+func FlatMap[T, U any](source <-chan trx.Result[T], project func(value T, index int) <-chan trx.Result[U], options ...Option) <-chan trx.Result[U] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[U](conf)
+
+	go func() {
+		defer close(out)
+
+		var wg sync.WaitGroup
+		defer wg.Wait()
+
+		slots := make(chan struct{}, max(conf.poolSize, 1))
+
+		for i := 0; ; i++ {
+			var v trx.Result[T]
+			var ok bool
+
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok = <-source:
+				if !ok {
+					return
+				}
+			}
+
+			value, err := v.Get()
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- trx.Err[U](err):
+				}
+
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case slots <- struct{}{}:
+			}
+
+			wg.Add(1)
+			go func(inner <-chan trx.Result[U]) {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case r, ok := <-inner:
+						if !ok {
+							return
+						}
+
+						select {
+						case <-ctx.Done():
+							return
+						case out <- r:
+						}
+					}
+				}
+			}(project(value, i))
+		}
+	}()
+
+	return out
+}
+
+// ConcatMap maps each value received from the source channel to an inner channel and emits the results of
+// the inner channels one after another, in source order: an inner channel is only consumed once the previous
+// one is closed. It behaves like FlatMap with a pool size of 1, regardless of WithPoolSize. Errors from the
+// source and from the inner channels are forwarded downstream, and an error from one inner channel does not
+// stop the source or the following inner channels. The output channel is closed once the source and all
+// inner channels are closed.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of values from the inner channels.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	project - A function that returns the inner channel for a value and its index.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the values of all inner channels in order, or errors.
+//
+// Example usage:
+//
+//	out := ConcatMap(pages, func(page int, _ int) <-chan trx.Result[Item] {
+//	    return fetchPage(page)
+//	})
+func ConcatMap[T, U any](source <-chan trx.Result[T], project func(value T, index int) <-chan trx.Result[U], options ...Option) <-chan trx.Result[U] {
+	return FlatMap(source, project, append(slices.Clip(options), WithPoolSize(1))...)
+}
+
+// SwitchMap maps each value received from the source channel to an inner channel, like FlatMap, but only
+// relays the results of the most recent inner channel: whenever a new value arrives, the previous inner
+// channel is abandoned and the output switches to the new one. This suits cases such as type-ahead search,
+// where the results of a stale query should be dropped. Errors from the source and from the active inner
+// channel are forwarded downstream. The output channel is closed once the source and the last inner channel
+// are closed.
+//
+// An abandoned inner channel is drained in the background until it is closed, so its producer is not left
+// blocked, but SwitchMap cannot stop that producer: inner channels must end on their own, or a background
+// goroutine is left behind for each switch away from an endless inner channel such as Interval. Use
+// SwitchMapContext to build inner channels that stop when they are switched away from.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of values from the inner channels.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	project - A function that returns the inner channel for a value and its index.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the values of the latest inner channel or errors.
+//
+// Example usage:
+//
+//	out := SwitchMap(queries, func(query string, _ int) <-chan trx.Result[Suggestion] {
+//	    return search(query)
+//	})
+func SwitchMap[T, U any](source <-chan trx.Result[T], project func(value T, index int) <-chan trx.Result[U], options ...Option) <-chan trx.Result[U] {
+	return SwitchMapContext(source, func(_ context.Context, value T, index int) <-chan trx.Result[U] {
+		return project(value, index)
+	}, options...)
+}
+
+// SwitchMapContext behaves like SwitchMap, but project also receives the context of the inner channel it
+// creates. That context is a child of the operator's context and is cancelled as soon as the output
+// switches to a newer inner channel, so a producer that honours it stops instead of running on unread,
+// for example an Interval created with WithContext.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of values from the inner channels.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	project - A function that returns the inner channel for a context, a value and its index.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the values of the latest inner channel or errors.
+//
+// Example usage:
+//
+//	out := SwitchMapContext(settings, func(ctx context.Context, s Settings, _ int) <-chan trx.Result[Reading] {
+//	    return poll(ctx, s.Interval)
+//	})
+func SwitchMapContext[T, U any](source <-chan trx.Result[T], project func(ctx context.Context, value T, index int) <-chan trx.Result[U], options ...Option) <-chan trx.Result[U] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[U](conf)
+
+	go func() {
+		defer close(out)
+
+		cancel := context.CancelFunc(func() {})
+		done := make(chan struct{})
+		close(done)
+
+		defer func() {
+			cancel()
+			<-done
+		}()
+
+		for i := 0; ; i++ {
+			var v trx.Result[T]
+			var ok bool
+
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok = <-source:
+				if !ok {
+					// Let the last inner channel run to completion.
+					select {
+					case <-ctx.Done():
+					case <-done:
+					}
+
+					return
+				}
+			}
+
+			value, err := v.Get()
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- trx.Err[U](err):
+				}
+
+				continue
+			}
+
+			// Stop relaying the previous inner channel before switching, so none of its results follow the switch.
+			cancel()
+			<-done
+
+			innerCtx, innerCancel := context.WithCancel(ctx)
+			cancel = innerCancel
+			done = make(chan struct{})
+
+			go func(inner <-chan trx.Result[U], done chan<- struct{}) {
+				defer close(done)
+
+				for {
+					select {
+					case <-innerCtx.Done():
+						go drain(inner)
+
+						return
+					case r, ok := <-inner:
+						if !ok {
+							return
+						}
+
+						select {
+						case <-innerCtx.Done():
+							go drain(inner)
+
+							return
+						case out <- r:
+						}
+					}
+				}
+			}(project(innerCtx, value, i), done)
+		}
+	}()
+
+	return out
+}
+
+// MapResizable behaves like Map but runs the mapper on a worker pool whose size can be changed while the
+// stream is running, which suits long-running pipelines with variable load. The initial size is taken from
+// WithPoolSize. The returned resize function sets the maximum number of concurrent mapper calls; shrinking
+// never drops in-flight work, it only delays new work until enough workers have finished. Results are
+// emitted in completion order, so WithSerialize has no effect.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	mapper - A function that maps each value and its index to a new value of type U, possibly returning an error.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the mapped results or errors, and a function that
+//	changes the pool size (values below 1 are treated as 1).
+//
+// Example usage:
+//
+//	out, resize := MapResizable(jobs, process, WithPoolSize(2))
+//	resize(8) // Scale up under load
+func MapResizable[T, U any](source <-chan trx.Result[T], mapper func(value T, index int) (U, error), options ...Option) (<-chan trx.Result[U], func(newSize int)) {
+	conf := parseOption(options...)
+	resizable := newResizablePool(conf.poolSize)
+
+	return Map(source, mapper, append(slices.Clip(options), withOwnPool(resizable))...), resizable.Resize
+}
+
+// MapWithDeadLetter behaves like Map but routes failures to a separate dead-letter channel instead of mixing
+// them into the main output. Whenever the mapper returns an error, the original input is emitted on the
+// dead-letter channel together with that error, for example to reprocess it later. Errors received from the
+// source have no input value to report, so they are still sent on the main output. Both channels are closed
+// together, and both must be drained, since a full channel blocks the other one.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	mapper - A function that maps each value and its index to a new value of type U, possibly returning an error.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the mapped results and source errors, and a
+//	receive-only channel of trx.DeadLetter[T] containing the inputs whose mapping failed.
+//
+// Example usage:
+//
+//	out, failed := MapWithDeadLetter(messages, handle)
+//	go func() {
+//	    for letter := range failed {
+//	        retryQueue.Push(letter.Value)
+//	    }
+//	}()
+func MapWithDeadLetter[T, U any](source <-chan trx.Result[T], mapper func(value T, index int) (U, error), options ...Option) (<-chan trx.Result[U], <-chan trx.DeadLetter[T]) {
+	type outcome struct {
+		value  U
+		failed *trx.DeadLetter[T]
+	}
+
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[U](conf)
+	deadLetters := make(chan trx.DeadLetter[T], conf.bufferSize)
+
+	outcomes := Map(source, func(value T, index int) (outcome, error) {
+		mapped, err := mapper(value, index)
+		if err != nil {
+			return outcome{failed: &trx.DeadLetter[T]{Value: value, Err: err}}, nil
+		}
+
+		return outcome{value: mapped}, nil
+	}, options...)
+
+	go func() {
+		defer close(out)
+		defer close(deadLetters)
+
+		for r := range outcomes {
+			o, err := r.Get()
+			if o.failed != nil {
+				select {
+				case <-ctx.Done():
+					go drain(outcomes)
+
+					return
+				case deadLetters <- *o.failed:
+				}
+
+				continue
+			}
+
+			result := trx.Ok(o.value)
+			if err != nil {
+				result = trx.Err[U](err)
+			}
+
+			select {
+			case <-ctx.Done():
+				go drain(outcomes)
+
+				return
+			case out <- result:
+			}
+		}
+	}()
+
+	return out, deadLetters
+}
+
+// MapWithProgress behaves like Map and additionally reports progress on a second channel as the fraction
+// of the expected total that has been emitted so far (count/total). This lets long batch jobs drive a
+// progress bar. The progress channel only keeps the latest report, so a slow or absent reader never blocks
+// the pipeline; it is closed together with the result channel. If total is not positive, no progress is
+// reported.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	total  - The expected number of items, used to compute the progress fraction.
+//	mapper - A function that maps each value and its index to a new value of type U, possibly returning an error.
+//	options
+//	    - WithBufferSize
+//	    - WithPoolSize
+//	    - WithSerialize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the mapped results or errors, and a receive-only
+//	channel of float64 reporting the fraction of items processed.
+//
+// Example usage:
+//
+//	out, progress := MapWithProgress(FormSlice(files), len(files), process)
+//	go func() {
+//	    for p := range progress {
+//	        bar.Set(p)
+//	    }
+//	}()
+func MapWithProgress[T, U any](source <-chan trx.Result[T], total int, mapper func(value T, index int) (U, error), options ...Option) (<-chan trx.Result[U], <-chan float64) {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[U](conf)
+	progress := make(chan float64, 1)
+
+	results := Map(source, mapper, options...)
+
+	go func() {
+		defer close(out)
+		defer close(progress)
+
+		count := 0
+		for r := range results {
+			select {
+			case <-ctx.Done():
+				go drain(results)
+
+				return
+			case out <- r:
+			}
+			count++
+
+			if total <= 0 {
+				continue
+			}
+
+			report := float64(count) / float64(total)
+			select {
+			case progress <- report:
+			default:
+				// Replace the stale report that nobody has read yet.
+				select {
+				case <-progress:
+				default:
+				}
+				progress <- report
+			}
+		}
+	}()
+
+	return out, progress
+}
+
+// BufferWithCount collects items from the source channel into fixed-size buffers and emits them as slices.
+// Each emitted slice contains up to 'count' items. If the source channel closes and there are remaining items
+// that do not fill a complete buffer, the final slice will contain the remaining items.
+//
+// The function supports optional configuration via Option parameters, such as context control and buffer size.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	count   - The number of items per buffer (must be > 0).
+//	options
+//	    - WithBufferSize
+//	    - WithCopyBatches
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[[]T] containing the buffered slices or errors.
+//
+// Example usage:
+//
+//	out := BufferWithCount(source, 3, WithBufferSize(10), WithContext(ctx))
+func BufferWithCount[T any](source <-chan trx.Result[T], count int, options ...Option) <-chan trx.Result[[]T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[[]T](conf)
+
+	go func() {
+		defer close(out)
+
+		buffer := make([]T, 0, count)
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[[]T](err)
+
+					return
+				}
+
+				buffer = append(buffer, value)
+				if len(buffer) >= count {
+					out <- trx.Ok(batchOf(conf, buffer))
+
+					buffer = make([]T, 0, count)
+				}
+			}
+		}
+
+		if len(buffer) > 0 {
+			out <- trx.Ok(batchOf(conf, buffer))
+		}
+	}()
+
+	return out
+}
+
+// BufferUntilCommit collects items from the source channel and emits everything collected so far as a single
+// slice each time the commit channel signals, which supports externally paced batch processing. At most
+// 'maxPending' items are held at a time: once the buffer is full, the source is no longer read until the
+// next commit, applying backpressure upstream. A commit with nothing pending emits nothing.
+//
+// When the source closes, the remaining items are emitted as a final slice. When the commit channel closes,
+// the pending items are emitted and the stream completes without reading further from the source.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source     - A receive-only channel of trx.Result[T] representing the input stream.
+//	commit     - A receive-only channel whose signals flush the pending items.
+//	maxPending - The maximum number of items buffered between commits. Values less than 1 are treated as 1.
+//	options
+//	    - WithBufferSize
+//	    - WithCopyBatches
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[[]T] containing the committed slices or errors.
+//
+// Example usage:
+//
+//	out := BufferUntilCommit(source, commits, 100)
+func BufferUntilCommit[T any](source <-chan trx.Result[T], commit <-chan struct{}, maxPending int, options ...Option) <-chan trx.Result[[]T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[[]T](conf)
+	maxPending = max(maxPending, 1)
+
+	go func() {
+		defer close(out)
+
+		buffer := make([]T, 0, maxPending)
+	LOOP:
+		for {
+			input := source
+			if len(buffer) >= maxPending {
+				input = nil // Stop reading until the next commit
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-commit:
+				if !ok {
+					break LOOP
+				}
+
+				if len(buffer) > 0 {
+					out <- trx.Ok(batchOf(conf, buffer))
+
+					buffer = make([]T, 0, maxPending)
+				}
+			case v, ok := <-input:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[[]T](err)
+
+					return
+				}
+
+				buffer = append(buffer, value)
+			}
+		}
+
+		if len(buffer) > 0 {
+			out <- trx.Ok(batchOf(conf, buffer))
+		}
+	}()
+
+	return out
+}
+
+// BufferWithTime collects items from the source channel into time-based buffers and emits them as slices.
+// Each emitted slice contains items collected within the specified duration or up to 'maxSize' items.
+// If 'maxSize' is 0, the buffer is emitted only based on the timer. If the source channel closes and there
+// are remaining items that do not fill a complete buffer, the final slice will contain the remaining items.
+// With WithAlignToClock, windows end on boundaries of the configured clock that are multiples of d, so the
+// first window may be shorter than d, and a flush caused by 'maxSize' does not move the later boundaries.
+//
+// The function supports optional configuration via Option parameters, such as context control and buffer size.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	d       - The duration to wait before emitting the buffer.
+//	maxSize - The maximum number of items per buffer (if 0, only time is considered).
+//	options
+//	    - WithBufferSize
+//	    - WithCopyBatches
+//	    - WithContext
+//	    - WithAlignToClock
+//	    - WithClock
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[[]T] containing the buffered slices or errors.
+//
+// Example usage:
+//
+//	out := BufferWithTime(source, time.Second, 5, WithBufferSize(10), WithContext(ctx))
+func BufferWithTime[T any](source <-chan trx.Result[T], d time.Duration, maxSize int, options ...Option) <-chan trx.Result[[]T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[[]T](conf)
+
+	go func() {
+		defer close(out)
+
+		buffer := make([]T, 0, max(maxSize, 0))
+
+		// With WithAlignToClock, every window ends on the next multiple of d on the configured clock, and size
+		// flushes leave the boundaries untouched; otherwise a ticker restarts with each size flush.
+		var (
+			ticker  *time.Ticker
+			tick    <-chan time.Time
+			aligned <-chan time.Time
+		)
+		nextBoundary := func() <-chan time.Time {
+			now := conf.clock.Now()
+
+			return conf.clock.After(now.Truncate(d).Add(d).Sub(now))
+		}
+
+		if conf.align {
+			aligned = nextBoundary()
+		} else {
+			ticker = time.NewTicker(d)
+			defer ticker.Stop()
+
+			tick = ticker.C
+		}
+
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-aligned:
+				if len(buffer) > 0 {
+					out <- trx.Ok(batchOf(conf, buffer))
+					buffer = make([]T, 0, batchCapacity(maxSize, len(buffer)))
+				}
+
+				aligned = nextBoundary()
+			case <-tick:
+				if len(buffer) > 0 {
+					out <- trx.Ok(batchOf(conf, buffer))
+					buffer = make([]T, 0, batchCapacity(maxSize, len(buffer)))
+				}
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[[]T](err)
+
+					return
+				}
+
+				buffer = append(buffer, value)
+				if maxSize > 0 && len(buffer) >= maxSize {
+					out <- trx.Ok(batchOf(conf, buffer))
+					buffer = make([]T, 0, batchCapacity(maxSize, len(buffer)))
+
+					if ticker != nil {
+						ticker.Reset(d)
+					}
+				}
+			}
+		}
+
+		if len(buffer) > 0 {
+			out <- trx.Ok(batchOf(conf, buffer))
+		}
+	}()
+
+	return out
+}
+
+// BufferWithTimeStamped behaves like BufferWithTime but emits each batch as a trx.TimedBatch carrying the
+// time window it covers, so downstream stages can aggregate or label data per time bucket. Windows follow
+// each other without gaps: a window normally lasts d, ends early when it reaches 'maxSize' items (if
+// 'maxSize' is greater than 0), and the next window starts where the previous one ended. Windows without
+// items are not emitted. If the source channel closes, the remaining items are emitted in a final window
+// ending at that moment. With WithAlignToClock, the first window ends on the next wall-clock boundary that
+// is a multiple of d. Times are read from the configured Clock, so a fake clock can be used in tests.
+//
+// The function supports optional configuration via Option parameters, such as context control and buffer size.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	d       - The duration of each window.
+//	maxSize - The maximum number of items per batch (0 = unlimited).
+//	options
+//	    - WithBufferSize
+//	    - WithCopyBatches
+//	    - WithContext
+//	    - WithAlignToClock
+//	    - WithClock
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[trx.TimedBatch[T]] containing the timed batches or errors.
+//
+// Example usage:
+//
+//	out := BufferWithTimeStamped(events, time.Minute, 0)
+//	for res := range out {
+//	    batch := res.Unwrap()
+//	    store.Save(batch.Start, len(batch.Items))
+//	}
+func BufferWithTimeStamped[T any](source <-chan trx.Result[T], d time.Duration, maxSize int, options ...Option) <-chan trx.Result[trx.TimedBatch[T]] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[trx.TimedBatch[T]](conf)
+
+	go func() {
+		defer close(out)
+
+		buffer := make([]T, 0, max(maxSize, 0))
+		start := conf.clock.Now()
+
+		first := d
+		if conf.align {
+			first = start.Truncate(d).Add(d).Sub(start)
+		}
+		window := conf.clock.After(first)
+
+		// flush emits the current window, if it has items, and starts the next one.
+		flush := func() {
+			end := conf.clock.Now()
+			if len(buffer) > 0 {
+				out <- trx.Ok(trx.TimedBatch[T]{Items: batchOf(conf, buffer), Start: start, End: end})
+				buffer = make([]T, 0, batchCapacity(maxSize, len(buffer)))
+			}
+
+			start = end
+			window = conf.clock.After(d)
+		}
+
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-window:
+				flush()
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[trx.TimedBatch[T]](err)
+
+					return
+				}
+
+				buffer = append(buffer, value)
+				if maxSize > 0 && len(buffer) >= maxSize {
+					flush()
+				}
+			}
+		}
+
+		if len(buffer) > 0 {
+			out <- trx.Ok(trx.TimedBatch[T]{Items: batchOf(conf, buffer), Start: start, End: conf.clock.Now()})
+		}
+	}()
+
+	return out
+}
+
+// BufferWithTimeOrCount collects items from the source channel into buffers and emits them as slices
+// either when the specified time duration has elapsed or when the buffer reaches the specified count, whichever comes first.
+// If the source channel closes and there are remaining items that do not fill a complete buffer, the final slice will contain the remaining items.
+//
+// The function supports optional configuration via Option parameters, such as context control and buffer size.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	d       - The duration to wait before emitting the buffer.
+//	count   - The maximum number of items per buffer (must be > 0).
+//	options
+//	    - WithBufferSize
+//	    - WithCopyBatches
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[[]T] containing the buffered slices or errors.
+//
+// Example usage:
+//
+//	out := BufferWithTimeOrCount(source, time.Second, 5, WithBufferSize(10), WithContext(ctx))
+func BufferWithTimeOrCount[T any](source <-chan trx.Result[T], d time.Duration, count int, options ...Option) <-chan trx.Result[[]T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[[]T](conf)
+
+	go func() {
+		defer close(out)
+
+		buffer := make([]T, 0, max(count, 0))
+
+		timer := time.NewTicker(d)
+		defer timer.Stop()
+
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				if len(buffer) > 0 {
+					out <- trx.Ok(batchOf(conf, buffer))
+					buffer = make([]T, 0, batchCapacity(count, len(buffer)))
+				}
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[[]T](err)
+
+					return
+				}
+
+				buffer = append(buffer, value)
+				if count > 0 && len(buffer) >= count {
+					out <- trx.Ok(batchOf(conf, buffer))
+					buffer = make([]T, 0, batchCapacity(count, len(buffer)))
+				}
+			}
+		}
+
+		if len(buffer) > 0 {
+			out <- trx.Ok(batchOf(conf, buffer))
+		}
+	}()
+
+	return out
+}
+
+// BufferAdaptive collects items from the source channel into batches that balance latency against batch
+// efficiency, which suits bulk sinks such as database writers. A batch starts with its first item and is
+// emitted as soon as it holds at least 'targetCount' items, but never before 'minTime' has elapsed since it
+// started, so bursts of tiny batches are smoothed out; items keep being collected during that hold, so a
+// batch may grow beyond targetCount. Regardless of its size, a batch is emitted once 'maxTime' has elapsed.
+// If the source channel closes, the remaining items are emitted as a final batch.
+//
+// The function supports optional configuration via Option parameters, such as context control and buffer size.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source      - A receive-only channel of trx.Result[T] representing the input stream.
+//	minTime     - The minimum time a batch is held before it can be emitted for reaching targetCount.
+//	maxTime     - The maximum time a batch is held before it is emitted.
+//	targetCount - The number of items that triggers an emission once minTime has elapsed.
+//	options
+//	    - WithBufferSize
+//	    - WithCopyBatches
+//	    - WithClock
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[[]T] containing the batches or errors.
+//
+// Example usage:
+//
+//	out := BufferAdaptive(rows, 50*time.Millisecond, time.Second, 500)
+func BufferAdaptive[T any](source <-chan trx.Result[T], minTime, maxTime time.Duration, targetCount int, options ...Option) <-chan trx.Result[[]T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[[]T](conf)
+
+	go func() {
+		defer close(out)
+
+		buffer := make([]T, 0, max(targetCount, 0))
+
+		var minTimer, maxTimer <-chan time.Time
+		held := false // Whether minTime has not yet elapsed for the current batch
+
+		flush := func() {
+			out <- trx.Ok(batchOf(conf, buffer))
+			buffer = make([]T, 0, batchCapacity(targetCount, len(buffer)))
+			minTimer, maxTimer = nil, nil
+		}
+
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-minTimer:
+				minTimer = nil
+				held = false
+
+				if len(buffer) >= targetCount {
+					flush()
+				}
+			case <-maxTimer:
+				flush()
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[[]T](err)
+
+					return
+				}
+
+				if len(buffer) == 0 {
+					minTimer = conf.clock.After(minTime)
+					maxTimer = conf.clock.After(maxTime)
+					held = true
+				}
+
+				buffer = append(buffer, value)
+				if !held && len(buffer) >= targetCount {
+					flush()
+				}
+			}
+		}
+
+		if len(buffer) > 0 {
+			out <- trx.Ok(batchOf(conf, buffer))
+		}
+	}()
+
+	return out
+}
+
+// MapMulti applies the provided mapper function to each item received from the source channel, letting the
+// mapper call emit zero or more times per input to produce output values. The values are sent downstream as
+// they are emitted, which flattens the output without building an intermediate slice and suits generators.
+// The mapper receives the value and its index in the sequence. The emit function must not be used after the
+// mapper has returned.
+//
+// If the mapper returns an error, the values it already emitted are kept, the error is sent downstream
+// wrapped in a trx.Result and processing continues with the next value. Errors received from the source are
+// forwarded in the same way. Values are processed one at a time, in order.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	mapper - A function that emits any number of values for each value and its index, possibly returning an error.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the emitted values or errors.
+//
+// Example usage:
+//
+//	out := MapMulti(orders, func(o Order, _ int, emit func(Item)) error {
+//	    for _, item := range o.Items {
+//	        emit(item)
+//	    }
+//	    return nil
+//	})
+func MapMulti[T, U any](source <-chan trx.Result[T], mapper func(value T, index int, emit func(U)) error, options ...Option) <-chan trx.Result[U] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[U](conf)
+
+	go func() {
+		defer close(out)
+
+		emit := func(v U) {
+			out <- trx.Ok(v)
+		}
+
+		i := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				value, err := v.Get()
+				if err == nil {
+					err = mapper(value, i, emit)
+				}
+
+				if err != nil {
+					out <- trx.Err[U](err)
+				}
+
+				i++
+			}
+		}
+	}()
+
+	return out
+}
+
+// MapBatched collects items from the source channel into batches of 'batchSize' and passes each whole
+// batch to the mapper, emitting the elements of the returned slice individually. This suits bulk
+// operations such as a batched database query. The mapper is free to return more or fewer elements than
+// it received, so the number of output items does not have to match the number of input items.
+// If the source channel closes with an incomplete batch, the remaining items are mapped as a final batch.
+//
+// If the mapper returns an error, the error is sent downstream wrapped in a trx.Result and processing
+// continues with the next batch. If an error is received from the source, the current batch is discarded,
+// the error is sent downstream and iteration stops.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//	U - The type of output values after mapping.
+//
+// Parameters:
+//
+//	source    - A receive-only channel of trx.Result[T] representing the input stream.
+//	batchSize - The number of items passed to the mapper at once (must be > 0).
+//	mapper    - A function that maps a batch of values to a slice of new values, possibly returning an error.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[U] containing the mapped results or errors.
+//
+// Example usage:
+//
+//	out := MapBatched(source, 100, func(ids []int) ([]User, error) {
+//	    return db.FindUsers(ids)
+//	})
+func MapBatched[T, U any](source <-chan trx.Result[T], batchSize int, mapper func(batch []T) ([]U, error), options ...Option) <-chan trx.Result[U] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[U](conf)
+
+	go func() {
+		defer close(out)
+
+		flush := func(batch []T) {
+			mapped, err := mapper(batch)
+			if err != nil {
+				out <- trx.Err[U](err)
+
+				return
+			}
+
+			for _, m := range mapped {
+				out <- trx.Ok(m)
+			}
+		}
+
+		batch := make([]T, 0, batchSize)
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					break LOOP
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[U](err)
+
+					return
+				}
+
+				batch = append(batch, value)
+				if len(batch) >= batchSize {
+					flush(batch)
+
+					batch = make([]T, 0, batchSize)
+				}
+			}
+		}
+
+		if len(batch) > 0 {
+			flush(batch)
+		}
+	}()
+
+	return out
+}
+
+// StateMachine runs a Mealy machine over the source channel. For each value, transition receives the current
+// state and the input and returns the next state together with zero or more outputs, which are emitted in
+// order. This is useful for protocol parsing or tokenizing over a stream.
+//
+// If transition returns an error, or an error is received from the source, the error is sent downstream
+// wrapped in a trx.Result and the machine stops.
+//
+// Type Parameters:
+//
+//	T - The type of input and output values.
+//	S - The type of the machine state.
+//
+// Parameters:
+//
+//	source     - A receive-only channel of trx.Result[T] representing the input stream.
+//	initial    - The initial state of the machine.
+//	transition - A function that computes the next state and the outputs for an input, possibly returning an error.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the outputs of the machine or errors.
+//
+// Example usage:
+//
+//	out := StateMachine(chars, "", func(word string, c string) (string, []string, error) {
+//	    if c == " " {
+//	        return "", []string{word}, nil
+//	    }
+//	    return word + c, nil, nil
+//	})
+func StateMachine[T, S any](source <-chan trx.Result[T], initial S, transition func(state S, input T) (S, []T, error), options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		state := initial
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					return
+				}
+
+				next, outputs, err := transition(state, value)
+				if err != nil {
+					out <- trx.Err[T](err)
+
+					return
+				}
+
+				state = next
+				for _, o := range outputs {
+					out <- trx.Ok(o)
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Debatch flattens a channel of slices into a channel of individual values, attaching to each value a global
+// running index that continues across batches. This is useful when values were batched for bulk processing
+// but downstream still needs their original positions. Errors received from the source are forwarded
+// downstream and do not advance the index.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of values inside the batches.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[[]T] representing the batched input stream.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[trx.Indexed[T]] containing the flattened values with their index, or errors.
+//
+// Example usage:
+//
+//	out := Debatch(BufferWithCount(source, 100))
+func Debatch[T any](source <-chan trx.Result[[]T], options ...Option) <-chan trx.Result[trx.Indexed[T]] {
 	conf := parseOption(options...)
 	ctx := makeContext(conf)
-	out := makeResultChannel[U](conf)
-	pool := makePool(conf)
+	out := makeResultChannel[trx.Indexed[T]](conf)
 
 	go func() {
 		defer close(out)
 
-		i := 0
-	LOOP:
+		index := 0
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case v, ok := <-source:
 				if !ok {
-					break LOOP
+					return
 				}
 
-				index := i
-				result := v
-
-				pool.submit(func() callback {
-					value, err := result.Get()
-					if err != nil {
-						return func() {
-							out <- trx.Err[U](err)
-						}
-					}
-
-					mapped, err := mapper(value, index)
-					if err != nil {
-						return func() {
-							out <- trx.Err[U](err)
-						}
-					}
+				batch, err := v.Get()
+				if err != nil {
+					out <- trx.Err[trx.Indexed[T]](err)
 
-					return func() {
-						out <- trx.Ok(mapped)
-					}
-				})
+					continue
+				}
 
-				i++
+				for _, value := range batch {
+					out <- trx.Ok(trx.Indexed[T]{Index: index, Value: value})
+					index++
+				}
 			}
 		}
-
-		pool.wait()
 	}()
 
 	return out
 }
 
-// BufferWithCount collects items from the source channel into fixed-size buffers and emits them as slices.
-// Each emitted slice contains up to 'count' items. If the source channel closes and there are remaining items
-// that do not fill a complete buffer, the final slice will contain the remaining items.
+// Rechunk re-batches a stream of slices of arbitrary sizes into chunks of exactly newSize items, splitting
+// and merging input slices as needed, for example when an upstream producer's batch sizes do not match the
+// size a downstream sink requires. Items keep their order. When the source closes, the remaining items are
+// emitted as a final, smaller chunk. Chunks never share memory with the input slices.
 //
-// The function supports optional configuration via Option parameters, such as context control and buffer size.
+// If an error is received from the source, it is sent downstream wrapped in a trx.Result and rechunking stops.
 //
 // Type Parameters:
 //
-//	T - The type of input values from the source channel.
+//	T - The type of items in the input slices.
 //
 // Parameters:
 //
-//	source  - A receive-only channel of trx.Result[T] representing the input stream.
-//	count   - The number of items per buffer (must be > 0).
+//	source  - A receive-only channel of trx.Result[[]T] representing the input stream of slices.
+//	newSize - The number of items per output chunk. Values less than 1 are treated as 1.
 //	options
 //	    - WithBufferSize
 //	    - WithContext
 //
 // Returns:
 //
-//	A receive-only channel of trx.Result[[]T] containing the buffered slices or errors.
+//	A receive-only channel of trx.Result[[]T] containing the uniform chunks or an error.
 //
 // Example usage:
 //
-//	out := BufferWithCount(source, 3, WithBufferSize(10), WithContext(ctx))
-func BufferWithCount[T any](source <-chan trx.Result[T], count int, options ...Option) <-chan trx.Result[[]T] {
+//	out := Rechunk(pages, 500) // The sink accepts exactly 500 rows per write
+func Rechunk[T any](source <-chan trx.Result[[]T], newSize int, options ...Option) <-chan trx.Result[[]T] {
 	conf := parseOption(options...)
 	ctx := makeContext(conf)
 	out := makeResultChannel[[]T](conf)
 
+	newSize = max(newSize, 1)
+
 	go func() {
 		defer close(out)
 
-		buffer := make([]T, 0, count)
+		chunk := make([]T, 0, newSize)
 	LOOP:
 		for {
 			select {
@@ -136,80 +1816,192 @@ func BufferWithCount[T any](source <-chan trx.Result[T], count int, options ...O
 					break LOOP
 				}
 
-				value, err := v.Get()
+				batch, err := v.Get()
 				if err != nil {
 					out <- trx.Err[[]T](err)
 
 					return
 				}
 
-				buffer = append(buffer, value)
-				if len(buffer) >= count {
-					out <- trx.Ok(buffer)
+				for len(batch) > 0 {
+					n := min(newSize-len(chunk), len(batch))
+					chunk = append(chunk, batch[:n]...)
+					batch = batch[n:]
 
-					buffer = make([]T, 0, count)
+					if len(chunk) < newSize {
+						break
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case out <- trx.Ok(chunk):
+					}
+
+					chunk = make([]T, 0, newSize)
 				}
 			}
 		}
 
-		if len(buffer) > 0 {
-			out <- trx.Ok(buffer)
+		if len(chunk) > 0 {
+			out <- trx.Ok(chunk)
 		}
 	}()
 
 	return out
 }
 
-// BufferWithTime collects items from the source channel into time-based buffers and emits them as slices.
-// Each emitted slice contains items collected within the specified duration or up to 'maxSize' items.
-// If 'maxSize' is 0, the buffer is emitted only based on the timer. If the source channel closes and there
-// are remaining items that do not fill a complete buffer, the final slice will contain the remaining items.
+// Coalesce combines runs of adjacent values into single values, for example summing consecutive records
+// that share a timestamp. Each value is compared with the pending one using canMerge: when it returns true,
+// the two are combined with merge and the result becomes the pending value; otherwise the pending value is
+// emitted and the new value takes its place. The pending value is emitted when the source closes.
 //
-// The function supports optional configuration via Option parameters, such as context control and buffer size.
+// Errors from the source break the current run: the pending value is emitted first, followed by the error,
+// and coalescing starts over with the next value. If the context is cancelled, the pending value is discarded.
 //
 // Type Parameters:
 //
-//	T - The type of input values from the source channel.
+//	T - The type of values from the source channel.
 //
 // Parameters:
 //
-//	source  - A receive-only channel of trx.Result[T] representing the input stream.
-//	d       - The duration to wait before emitting the buffer.
-//	maxSize - The maximum number of items per buffer (if 0, only time is considered).
+//	source   - A receive-only channel of trx.Result[T] representing the input stream.
+//	canMerge - A function that reports whether the pending value a and the next value b can be combined.
+//	merge    - A function that combines the pending value a with the next value b.
 //	options
 //	    - WithBufferSize
 //	    - WithContext
 //
 // Returns:
 //
-//	A receive-only channel of trx.Result[[]T] containing the buffered slices or errors.
+//	A receive-only channel of trx.Result[T] containing the combined values or errors.
 //
 // Example usage:
 //
-//	out := BufferWithTime(source, time.Second, 5, WithBufferSize(10), WithContext(ctx))
-func BufferWithTime[T any](source <-chan trx.Result[T], d time.Duration, maxSize int, options ...Option) <-chan trx.Result[[]T] {
+//	out := Coalesce(records, func(a, b Record) bool {
+//	    return a.Key == b.Key
+//	}, func(a, b Record) Record {
+//	    return Record{Key: a.Key, Amount: a.Amount + b.Amount}
+//	})
+func Coalesce[T any](source <-chan trx.Result[T], canMerge func(a, b T) bool, merge func(a, b T) T, options ...Option) <-chan trx.Result[T] {
 	conf := parseOption(options...)
 	ctx := makeContext(conf)
-	out := makeResultChannel[[]T](conf)
+	out := makeResultChannel[T](conf)
 
 	go func() {
 		defer close(out)
 
-		buffer := make([]T, 0)
+		send := func(r trx.Result[T]) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- r:
+				return true
+			}
+		}
 
-		timer := time.NewTicker(d)
-		defer timer.Stop()
+		var pending T
+		hasPending := false
 
-	LOOP:
 		for {
+			var v trx.Result[T]
+			var ok bool
+
 			select {
 			case <-ctx.Done():
 				return
-			case <-timer.C:
-				if len(buffer) > 0 {
-					out <- trx.Ok(buffer)
-					buffer = make([]T, 0)
+			case v, ok = <-source:
+			}
+
+			if !ok {
+				break
+			}
+
+			value, err := v.Get()
+			if err != nil {
+				if hasPending && !send(trx.Ok(pending)) {
+					return
+				}
+				hasPending = false
+
+				if !send(v) {
+					return
 				}
+
+				continue
+			}
+
+			if !hasPending {
+				pending, hasPending = value, true
+
+				continue
+			}
+
+			if canMerge(pending, value) {
+				pending = merge(pending, value)
+
+				continue
+			}
+
+			if !send(trx.Ok(pending)) {
+				return
+			}
+			pending = value
+		}
+
+		if hasPending {
+			send(trx.Ok(pending))
+		}
+	}()
+
+	return out
+}
+
+// Shuffle emits the values of the source channel in a randomized order, which de-correlates ordered data,
+// for example before training a model. It keeps a buffer of up to 'bufferSize' values; once the buffer is
+// full, each new value replaces a randomly chosen buffered value, which is emitted. When the source closes,
+// the remaining buffered values are emitted in shuffled order. Larger buffers shuffle more thoroughly at the
+// cost of memory and latency. The same seed always yields the same order for the same input.
+//
+// Errors received from the source are sent downstream immediately, wrapped in a trx.Result, and shuffling
+// continues with the next value.
+//
+// Type Parameters:
+//
+//	T - The type of input values from the source channel.
+//
+// Parameters:
+//
+//	source     - A receive-only channel of trx.Result[T] representing the input stream.
+//	bufferSize - The number of values held for shuffling. Values less than 1 are treated as 1 (no shuffling).
+//	seed       - The seed of the random order, for reproducible runs.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the source values in randomized order, or errors.
+//
+// Example usage:
+//
+//	out := Shuffle(samples, 1000, 42)
+func Shuffle[T any](source <-chan trx.Result[T], bufferSize int, seed int64, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+	bufferSize = max(bufferSize, 1)
+
+	go func() {
+		defer close(out)
+
+		random := rand.New(rand.NewPCG(uint64(seed), 0))
+		buffer := make([]T, 0, bufferSize)
+	LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return
 			case v, ok := <-source:
 				if !ok {
 					break LOOP
@@ -217,101 +2009,278 @@ func BufferWithTime[T any](source <-chan trx.Result[T], d time.Duration, maxSize
 
 				value, err := v.Get()
 				if err != nil {
-					out <- trx.Err[[]T](err)
+					out <- trx.Err[T](err)
 
-					return
+					continue
 				}
 
-				buffer = append(buffer, value)
-				if maxSize > 0 && len(buffer) >= maxSize {
-					out <- trx.Ok(buffer)
-					buffer = make([]T, 0)
-					timer.Reset(d)
+				if len(buffer) < bufferSize {
+					buffer = append(buffer, value)
+
+					continue
 				}
+
+				i := random.IntN(bufferSize)
+				out <- trx.Ok(buffer[i])
+				buffer[i] = value
 			}
 		}
 
-		if len(buffer) > 0 {
-			out <- trx.Ok(buffer)
+		random.Shuffle(len(buffer), func(i, j int) {
+			buffer[i], buffer[j] = buffer[j], buffer[i]
+		})
+
+		for _, value := range buffer {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- trx.Ok(value):
+			}
 		}
 	}()
 
 	return out
 }
 
-// BufferWithTimeOrCount collects items from the source channel into buffers and emits them as slices
-// either when the specified time duration has elapsed or when the buffer reaches the specified count, whichever comes first.
-// If the source channel closes and there are remaining items that do not fill a complete buffer, the final slice will contain the remaining items.
+// GroupByParallel partitions the source channel by key and processes each partition with its own
+// sub-pipeline, merging the outputs of all sub-pipelines into a single channel. For each new key, perKey is
+// called once with the key and a channel that receives every value of that key in source order, so
+// processing is ordered within a partition and parallel across partitions, for example to handle each user's
+// events in order. Each sub-pipeline lives for the whole stream, so stateful sub-pipelines such as Reduce
+// emit a single result per key. Errors received from the source have no key and are forwarded directly.
 //
-// The function supports optional configuration via Option parameters, such as context control and buffer size.
+// At most WithPoolSize partitions exist at a time (1 by default). A new key that finds no free slot waits
+// for one: its values are buffered until a running sub-pipeline completes, which happens when the source
+// completes or earlier if the sub-pipeline stops on its own, so every key still gets a single sub-pipeline.
+// Values of a key whose sub-pipeline has already completed are dropped. Delivering a value blocks until its
+// sub-pipeline reads it, so a sub-pipeline that does not keep up with its input slows down the others. Once
+// the source completes, the input channels are closed and the output channel is closed after all
+// sub-pipelines have completed.
 //
 // Type Parameters:
 //
 //	T - The type of input values from the source channel.
+//	K - The type of the keys (must be comparable).
+//	U - The type of values emitted by the sub-pipelines.
 //
 // Parameters:
 //
 //	source  - A receive-only channel of trx.Result[T] representing the input stream.
-//	d       - The duration to wait before emitting the buffer.
-//	count   - The maximum number of items per buffer (must be > 0).
+//	keyFunc - A function that returns the partition key of a value.
+//	perKey  - A function that builds the sub-pipeline of a partition from its key and input channel.
 //	options
 //	    - WithBufferSize
+//	    - WithPoolSize
 //	    - WithContext
 //
 // Returns:
 //
-//	A receive-only channel of trx.Result[[]T] containing the buffered slices or errors.
+//	A receive-only channel of trx.Result[U] containing the merged outputs of the sub-pipelines, and errors.
 //
 // Example usage:
 //
-//	out := BufferWithTimeOrCount(source, time.Second, 5, WithBufferSize(10), WithContext(ctx))
-func BufferWithTimeOrCount[T any](source <-chan trx.Result[T], d time.Duration, count int, options ...Option) <-chan trx.Result[[]T] {
+//	out := GroupByParallel(events, func(e Event) string { return e.UserID },
+//	    func(user string, events <-chan trx.Result[Event]) <-chan trx.Result[Receipt] {
+//	        return Map(events, applyInOrder)
+//	    }, WithPoolSize(32))
+func GroupByParallel[T any, K comparable, U any](source <-chan trx.Result[T], keyFunc func(value T) K, perKey func(key K, values <-chan trx.Result[T]) <-chan trx.Result[U], options ...Option) <-chan trx.Result[U] {
 	conf := parseOption(options...)
 	ctx := makeContext(conf)
-	out := makeResultChannel[[]T](conf)
+	out := makeResultChannel[U](conf)
 
 	go func() {
+		var wg sync.WaitGroup
 		defer close(out)
+		defer wg.Wait()
+
+		type partition struct {
+			in   chan trx.Result[T]
+			done chan struct{} // Closed once the output of the sub-pipeline has been relayed
+		}
 
-		buffer := make([]T, 0)
+		limit := max(conf.poolSize, 1)
+		finished := make(chan struct{}, limit) // One signal per completed partition, so relays never block on it
+		partitions := make(map[K]*partition)   // Started partitions, running or completed
+		fed := make(map[K]*partition)          // Partitions whose input is fed by this goroutine
+		waiting := make(map[K][]trx.Result[T]) // Values of keys waiting for a slot
+		var queue []K                          // Waiting keys in order of first appearance
+		running := 0
 
-		timer := time.NewTicker(d)
-		defer timer.Stop()
+		defer func() {
+			for _, p := range fed {
+				close(p.in)
+			}
+		}()
 
-	LOOP:
-		for {
+		start := func(key K) *partition {
+			p := &partition{in: make(chan trx.Result[T]), done: make(chan struct{})}
+			results := perKey(key, p.in)
+			partitions[key] = p
+			running++
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					close(p.done)
+					finished <- struct{}{}
+				}()
+
+				for r := range results {
+					select {
+					case <-ctx.Done():
+						go drain(results)
+
+						return
+					case out <- r:
+					}
+				}
+			}()
+
+			return p
+		}
+
+		// send delivers v to the sub-pipeline of p, dropping it if the sub-pipeline has already completed.
+		send := func(p *partition, v trx.Result[T]) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-p.done:
+				return true
+			case p.in <- v:
+				return true
+			}
+		}
+
+		// startWaiting starts the waiting partitions that fit in the free slots and feeds them their
+		// buffered values, from a separate goroutine once the source has completed.
+		startWaiting := func(completed bool) bool {
+			for running < limit && len(queue) > 0 {
+				key := queue[0]
+				queue = queue[1:]
+
+				values := waiting[key]
+				delete(waiting, key)
+
+				p := start(key)
+				if completed {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						defer close(p.in)
+
+						for _, v := range values {
+							if !send(p, v) {
+								return
+							}
+						}
+					}()
+
+					continue
+				}
+
+				fed[key] = p
+				for _, v := range values {
+					if !send(p, v) {
+						return false
+					}
+				}
+			}
+
+			return true
+		}
+
+		for source != nil {
 			select {
 			case <-ctx.Done():
 				return
-			case <-timer.C:
-				if len(buffer) > 0 {
-					out <- trx.Ok(buffer)
-					buffer = make([]T, 0)
+			case <-finished:
+				running--
+				if !startWaiting(false) {
+					return
 				}
 			case v, ok := <-source:
 				if !ok {
-					break LOOP
+					source = nil
+
+					continue
 				}
 
 				value, err := v.Get()
 				if err != nil {
-					out <- trx.Err[[]T](err)
+					select {
+					case <-ctx.Done():
+						return
+					case out <- trx.Err[U](err):
+					}
 
-					return
+					continue
 				}
 
-				buffer = append(buffer, value)
-				if count > 0 && len(buffer) >= count {
-					out <- trx.Ok(buffer)
-					buffer = make([]T, 0)
+				key := keyFunc(value)
+				if p, ok := partitions[key]; ok {
+					if !send(p, v) {
+						return
+					}
+
+					continue
+				}
+
+				values, isWaiting := waiting[key]
+				if !isWaiting && running < limit {
+					p := start(key)
+					fed[key] = p
+					if !send(p, v) {
+						return
+					}
+
+					continue
 				}
+
+				if !isWaiting {
+					queue = append(queue, key)
+				}
+				waiting[key] = append(values, v)
 			}
 		}
 
-		if len(buffer) > 0 {
-			out <- trx.Ok(buffer)
+		// The source has completed: close the inputs of the running partitions and start the waiting ones
+		// as slots are released.
+		for _, p := range fed {
+			close(p.in)
+		}
+		clear(fed)
+
+		for running > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-finished:
+			}
+
+			running--
+			startWaiting(true)
 		}
 	}()
 
 	return out
 }
+
+// batchOf returns the slice to emit for a full buffer, copying it when WithCopyBatches is set.
+func batchOf[T any](c *config, buffer []T) []T {
+	if c.copyBatches {
+		return slices.Clone(buffer)
+	}
+
+	return buffer
+}
+
+// batchCapacity returns the capacity hint for the next buffer of a time-based batching operator:
+// the size limit when there is one, otherwise the size of the previous batch.
+func batchCapacity(limit int, previous int) int {
+	if limit > 0 {
+		return limit
+	}
+
+	return previous
+}
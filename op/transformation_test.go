@@ -1,8 +1,15 @@
 package op_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -10,6 +17,7 @@ import (
 
 	"github.com/foreveralonet/trx"
 	"github.com/foreveralonet/trx/op"
+	"github.com/foreveralonet/trx/trxtest"
 )
 
 var _ = Describe("Transformation Operations", func() {
@@ -153,236 +161,2377 @@ var _ = Describe("Transformation Operations", func() {
 				Expect(results).To(Equal(expectedValues))
 			})
 		})
+
+		Context("when limiting the number of emissions", func() {
+			It("should emit exactly n values and stop reading the source", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				var reads atomic.Int64
+				source := make(chan trx.Result[int])
+				producerDone := make(chan struct{})
+
+				go func() {
+					defer close(producerDone)
+
+					for i := 0; ; i++ {
+						select {
+						case <-ctx.Done():
+							return
+						case source <- trx.Ok(i):
+							reads.Add(1)
+						}
+					}
+				}()
+
+				out := op.Map(source, func(value int, index int) (int, error) {
+					return value * 10, nil
+				}, op.WithLimit(3))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 10, 20}))
+				Eventually(reads.Load).Should(Equal(int64(3)))
+				Consistently(reads.Load, 30*time.Millisecond).Should(Equal(int64(3)))
+
+				cancel()
+				Eventually(producerDone).Should(BeClosed())
+			})
+
+			It("should cap emissions with concurrent processing", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel() // Release the Range producer left blocked once the limit is reached
+
+					out := op.Map(op.Range(0, 100, op.WithContext(ctx)), func(value int, index int) (int, error) {
+						return value, nil
+					}, op.WithPoolSize(4), op.WithLimit(5))
+
+					count := 0
+					for range out {
+						count++
+					}
+
+					Expect(count).To(Equal(5))
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when dropping results on backpressure", func() {
+			It("should drop results and keep running with a stalled consumer", func() {
+				var dropped atomic.Int64
+				var mapped atomic.Int64
+
+				out := op.Map(op.Range(0, 100), func(value int, index int) (int, error) {
+					mapped.Add(1)
+
+					return value, nil
+				}, op.WithBufferSize(2), op.WithDropOnBackpressure(), op.WithOnDrop(func() {
+					dropped.Add(1)
+				}))
+
+				// The consumer is stalled while the whole source gets processed
+				Eventually(mapped.Load).Should(Equal(int64(100)))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 1}))
+				Expect(dropped.Load()).To(Equal(int64(98)))
+			})
+
+			It("should never drop errors", func() {
+				var dropped atomic.Int64
+				testError := errors.New("late failure")
+
+				out := op.Map(op.Range(0, 15), func(value int, index int) (int, error) {
+					if value >= 10 {
+						return 0, testError
+					}
+
+					return value, nil
+				}, op.WithBufferSize(1), op.WithDropOnBackpressure(), op.WithOnDrop(func() {
+					dropped.Add(1)
+				}))
+
+				// With the consumer stalled, every value after the buffered one is dropped, and the errors wait.
+				Eventually(dropped.Load).Should(Equal(int64(9)))
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(6))
+				Expect(results[0].Unwrap()).To(Equal(0))
+				for _, result := range results[1:] {
+					Expect(result.Err()).To(Equal(testError))
+				}
+				Expect(dropped.Load()).To(Equal(int64(9)))
+			})
+		})
+
+		Context("when the mapper returns ErrStop", func() {
+			It("should complete the stream without an error item", func() {
+				out := op.Map(op.Range(0, 100), func(value int, index int) (int, error) {
+					if value == 3 {
+						return 0, op.ErrStop
+					}
+
+					return value * 10, nil
+				})
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				values := make([]int, 0)
+				for _, result := range results {
+					Expect(result.IsOk()).To(BeTrue())
+					values = append(values, result.Unwrap())
+				}
+				Expect(values).To(Equal([]int{0, 10, 20}))
+			})
+
+			It("should emit prior results in order with serialized processing", func() {
+				out := op.Map(op.Range(0, 100), func(value int, index int) (int, error) {
+					if value == 5 {
+						return 0, op.ErrStop
+					}
+
+					return value, nil
+				}, op.WithPoolSize(3), op.WithSerialize())
+
+				values := make([]int, 0)
+				for result := range out {
+					values = append(values, result.Unwrap())
+				}
+
+				Expect(values).To(Equal([]int{0, 1, 2, 3, 4}))
+			})
+
+			It("should keep the results of earlier items that finish after the stop", func() {
+				stopped := make(chan struct{})
+
+				out := op.Map(op.Range(0, 4), func(value int, index int) (int, error) {
+					switch value {
+					case 0:
+						<-stopped
+						time.Sleep(20 * time.Millisecond)
+
+						return 0, nil
+					case 1:
+						close(stopped)
+
+						return 0, op.ErrStop
+					default:
+						<-stopped
+						time.Sleep(20 * time.Millisecond)
+
+						return value * 10, nil
+					}
+				}, op.WithPoolSize(4))
+
+				values := make([]int, 0)
+				for result := range out {
+					values = append(values, result.Unwrap())
+				}
+
+				Expect(values).To(Equal([]int{0}))
+			})
+		})
+
+		Context("when using a source buffer", func() {
+			It("should map every value", func() {
+				out := op.Map(op.Range(0, 50), func(value int, index int) (int, error) {
+					return value + index, nil
+				}, op.WithPoolSize(3), op.WithSerialize(), op.WithSourceBuffer(8))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(HaveLen(50))
+				Expect(results[49]).To(Equal(98))
+			})
+		})
+
+		Context("when using lifecycle hooks", func() {
+			run := func(source <-chan trx.Result[int], mapper func(int, int) (int, error), options ...op.Option) (bool, op.StopReason) {
+				started := make(chan struct{})
+				stopped := make(chan op.StopReason, 1)
+
+				options = append(options,
+					op.WithOnStart(func() { close(started) }),
+					op.WithOnStop(func(reason op.StopReason) { stopped <- reason }),
+				)
+
+				out := op.Map(source, mapper, options...)
+				for range out {
+				}
+
+				var reason op.StopReason
+				Eventually(stopped).Should(Receive(&reason))
+
+				select {
+				case <-started:
+					return true, reason
+				default:
+					return false, reason
+				}
+			}
+
+			identity := func(value int, index int) (int, error) { return value, nil }
+
+			It("should report completion", func() {
+				started, reason := run(op.Range(0, 3), identity)
+				Expect(started).To(BeTrue())
+				Expect(reason).To(Equal(op.StopCompleted))
+			})
+
+			It("should report cancellation", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				started, reason := run(make(chan trx.Result[int]), identity, op.WithContext(ctx))
+				Expect(started).To(BeTrue())
+				Expect(reason).To(Equal(op.StopCancelled))
+			})
+
+			It("should report an error", func() {
+				started, reason := run(op.Range(0, 3), func(value int, index int) (int, error) {
+					if value == 1 {
+						return 0, errors.New("mapper error")
+					}
+
+					return value, nil
+				})
+				Expect(started).To(BeTrue())
+				Expect(reason).To(Equal(op.StopErrored))
+				Expect(reason.String()).To(Equal("errored"))
+			})
+		})
+
+		Context("with WithContiguousIndex", func() {
+			It("should pass 0-based contiguous indices after an upstream Filter", func() {
+				source := make(chan trx.Result[int], 9)
+				for i := 0; i < 6; i++ {
+					source <- trx.Ok(i)
+					if i%2 == 0 {
+						source <- trx.Err[int](errors.New("source error"))
+					}
+				}
+				close(source)
+
+				evens := op.Filter(source, func(v int, _ int) (bool, error) {
+					return v%2 == 0, nil
+				})
+
+				out := op.Map(evens, func(v int, index int) (int, error) {
+					return index, nil
+				}, op.WithContiguousIndex(), op.WithSerialize())
+
+				indices := make([]int, 0)
+				errs := 0
+				for result := range out {
+					if result.IsErr() {
+						errs++
+
+						continue
+					}
+					indices = append(indices, result.Unwrap())
+				}
+
+				Expect(indices).To(Equal([]int{0, 1, 2}))
+				Expect(errs).To(Equal(3))
+			})
+
+			It("should count errors in the index by default", func() {
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](errors.New("source error"))
+				source <- trx.Ok(2)
+				close(source)
+
+				out := op.Map(source, func(v int, index int) (int, error) {
+					return index, nil
+				}, op.WithSerialize())
+
+				indices := make([]int, 0)
+				for result := range out {
+					if result.IsOk() {
+						indices = append(indices, result.Unwrap())
+					}
+				}
+
+				Expect(indices).To(Equal([]int{0, 2}))
+			})
+		})
+
+		Context("with WithName and WithWrapErrors", func() {
+			It("should prefix forwarded errors with the stage name", func() {
+				sourceError := errors.New("source error")
+				mapperError := errors.New("mapper error")
+
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Err[int](sourceError)
+				source <- trx.Ok(1)
+				close(source)
+
+				out := op.Map(source, func(v int, _ int) (int, error) {
+					return 0, mapperError
+				}, op.WithName("parse"), op.WithWrapErrors(), op.WithSerialize())
+
+				errs := make([]error, 0)
+				for result := range out {
+					errs = append(errs, result.Err())
+				}
+
+				Expect(errs).To(HaveLen(2))
+				Expect(errs[0].Error()).To(Equal("parse: source error"))
+				Expect(errs[0]).To(MatchError(sourceError))
+				Expect(errs[1].Error()).To(Equal("parse: mapper error"))
+				Expect(errors.Is(errs[1], mapperError)).To(BeTrue())
+			})
+
+			It("should leave errors unchanged without WithWrapErrors", func() {
+				mapperError := errors.New("mapper error")
+				out := op.Map(op.Range(0, 1), func(v int, _ int) (int, error) {
+					return 0, mapperError
+				}, op.WithName("parse"))
+
+				result := <-out
+				Expect(result.Err()).To(Equal(mapperError))
+			})
+		})
+
+		Context("when the consumer abandons the stream", func() {
+			It("should release blocked workers once the context is cancelled", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+
+					out := op.Map(op.Range(0, 10000, op.WithContext(ctx)), func(v int, _ int) (int, error) {
+						return v, nil
+					}, op.WithPoolSize(8), op.WithContext(ctx))
+
+					count := 0
+					for range out {
+						count++
+						if count == 5 {
+							break
+						}
+					}
+
+					// Give the workers time to block on the abandoned output channel
+					time.Sleep(20 * time.Millisecond)
+					cancel()
+
+					Eventually(out).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("with WithEmitCancellationError", func() {
+			It("should not block when the consumer stopped reading before cancelling", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancelCause(context.Background())
+
+					out := op.Map(op.Range(0, 10000, op.WithContext(ctx)), func(v int, _ int) (int, error) {
+						return v, nil
+					}, op.WithPoolSize(8), op.WithContext(ctx), op.WithEmitCancellationError())
+
+					count := 0
+					for range out {
+						count++
+						if count == 5 {
+							break
+						}
+					}
+
+					cancel(errors.New("shutting down"))
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should emit the cancellation cause before closing", func() {
+				cause := errors.New("shutting down")
+				ctx, cancel := context.WithCancelCause(context.Background())
+
+				out := op.Map(make(chan trx.Result[int]), func(v int, _ int) (int, error) {
+					return v, nil
+				}, op.WithContext(ctx), op.WithEmitCancellationError())
+				cancel(cause)
+
+				var result trx.Result[int]
+				Eventually(out).Should(Receive(&result))
+				Expect(result.Err()).To(Equal(cause))
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should only close the channel without the option", func() {
+				ctx, cancel := context.WithCancelCause(context.Background())
+
+				out := op.Map(make(chan trx.Result[int]), func(v int, _ int) (int, error) {
+					return v, nil
+				}, op.WithContext(ctx))
+				cancel(errors.New("shutting down"))
+
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("with WithItemRetry", func() {
+			It("should retry a failing item until it succeeds", func() {
+				transientError := errors.New("transient")
+				var calls atomic.Int64
+
+				out := op.Map(op.Range(0, 5), func(v int, _ int) (int, error) {
+					if v == 3 && calls.Add(1) <= 2 {
+						return 0, transientError
+					}
+
+					return v * 10, nil
+				}, op.WithItemRetry(2, time.Millisecond))
+
+				results := make([]int, 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{0, 10, 20, 30, 40}))
+				Expect(calls.Load()).To(Equal(int64(3)))
+			})
+
+			It("should forward the last error once the retries are exhausted", func() {
+				var calls atomic.Int64
+
+				out := op.Map(op.Range(0, 1), func(v int, _ int) (int, error) {
+					return 0, fmt.Errorf("attempt %d", calls.Add(1))
+				}, op.WithItemRetry(2, time.Millisecond))
+
+				result := <-out
+				Expect(result.Err()).To(MatchError("attempt 3"))
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should stop waiting between attempts when the context is cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				var calls atomic.Int64
+
+				out := op.Map(op.Range(0, 1), func(v int, _ int) (int, error) {
+					calls.Add(1)
+
+					return 0, errors.New("transient")
+				}, op.WithItemRetry(5, time.Hour), op.WithContext(ctx))
+
+				Eventually(calls.Load).Should(Equal(int64(1)))
+				cancel()
+
+				Eventually(out).Should(BeClosed())
+				Expect(calls.Load()).To(Equal(int64(1)))
+			})
+		})
+
+		Context("with WithPool", func() {
+			It("should submit every task to the provided pool", func() {
+				pool := &countingPool{}
+				out := op.Map(op.Range(0, 20), func(v int, _ int) (int, error) {
+					return v * 2, nil
+				}, op.WithPool(pool))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				expected := make([]int, 20)
+				for i := range expected {
+					expected[i] = i * 2
+				}
+				Expect(results).To(ConsistOf(expected))
+				Expect(pool.submitted.Load()).To(Equal(int64(20)))
+			})
+
+			It("should not deadlock when a bounded pool is shared across stages", func() {
+				shared := newSemaphorePool(1)
+
+				mapped := op.Map(op.Range(0, 50), func(v int, _ int) (int, error) {
+					return v * 2, nil
+				}, op.WithPool(shared), op.WithPoolSize(4))
+
+				out := op.Filter(mapped, func(v int, _ int) (bool, error) {
+					return v%4 == 0, nil
+				}, op.WithPool(shared), op.WithPoolSize(4))
+
+				results, err := trxtest.Collect(out, 5*time.Second)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(HaveLen(25))
+			})
+
+			It("should run tasks concurrently up to the pool's capacity without WithPoolSize", func() {
+				var active, peak atomic.Int32
+
+				out := op.Map(op.Range(0, 12), func(v int, _ int) (int, error) {
+					n := active.Add(1)
+					for {
+						p := peak.Load()
+						if n <= p || peak.CompareAndSwap(p, n) {
+							break
+						}
+					}
+
+					time.Sleep(10 * time.Millisecond)
+					active.Add(-1)
+
+					return v, nil
+				}, op.WithPool(newSemaphorePool(4)))
+
+				results, err := trxtest.Collect(out, 5*time.Second)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(HaveLen(12))
+				Expect(peak.Load()).To(BeNumerically(">", 1))
+				Expect(peak.Load()).To(BeNumerically("<=", 4))
+			})
+
+			It("should emit in source order with WithSerialize or WithOrderedConcurrency", func() {
+				slowFirst := func(v int, _ int) (int, error) {
+					time.Sleep(time.Duration(10-v) * time.Millisecond)
+
+					return v, nil
+				}
+
+				for _, ordering := range []op.Option{op.WithSerialize(), op.WithOrderedConcurrency(4)} {
+					out := op.Map(op.Range(0, 10), slowFirst, op.WithPool(newSemaphorePool(4)), ordering)
+
+					results := make([]int, 0)
+					for result := range out {
+						results = append(results, result.Unwrap())
+					}
+
+					Expect(results).To(Equal([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}))
+				}
+			})
+
+			It("should stop waiting for a slot when the context is cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				release := make(chan struct{})
+				var calls atomic.Int32
+
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+
+				out := op.Map(source, func(v int, _ int) (int, error) {
+					calls.Add(1)
+					<-release // Holds the only slot
+
+					return v, nil
+				}, op.WithPool(newSemaphorePool(4)), op.WithPoolSize(1), op.WithContext(ctx))
+
+				Eventually(calls.Load).Should(Equal(int32(1)))
+				cancel()
+				time.Sleep(20 * time.Millisecond) // Let the operator give up waiting for the slot
+				close(release)
+
+				Eventually(out).Should(BeClosed())
+				Expect(calls.Load()).To(Equal(int32(1)))
+			})
+		})
+
+		Context("with WithOrderedConcurrency", func() {
+			It("should emit in order with bounded parallelism and reordering", func() {
+				const (
+					items  = 200
+					window = 8
+				)
+
+				var (
+					active     atomic.Int64
+					maxActive  atomic.Int64
+					maxStarted atomic.Int64
+				)
+				storeMax := func(v *atomic.Int64, n int64) {
+					for {
+						current := v.Load()
+						if n <= current || v.CompareAndSwap(current, n) {
+							return
+						}
+					}
+				}
+
+				out := op.Map(op.Range(0, items), func(v int, i int) (int, error) {
+					storeMax(&maxActive, active.Add(1))
+					storeMax(&maxStarted, int64(i))
+					defer active.Add(-1)
+
+					time.Sleep(time.Duration(rand.IntN(2000)) * time.Microsecond)
+
+					return v * 10, nil
+				}, op.WithOrderedConcurrency(window))
+
+				received := 0
+				for result := range out {
+					Expect(result.Unwrap()).To(Equal(received * 10))
+					received++
+
+					// Items are only started while they fit in the window past the oldest pending one
+					Expect(maxStarted.Load()).To(BeNumerically("<", received+window))
+				}
+
+				Expect(received).To(Equal(items))
+				Expect(maxActive.Load()).To(BeNumerically("<=", window))
+				Expect(maxActive.Load()).To(BeNumerically(">", 1))
+			})
+
+			It("should forward errors in their position", func() {
+				testError := errors.New("odd")
+				out := op.Map(op.Range(0, 6), func(v int, _ int) (int, error) {
+					time.Sleep(time.Duration(6-v) * time.Millisecond)
+					if v%2 == 1 {
+						return 0, testError
+					}
+
+					return v, nil
+				}, op.WithOrderedConcurrency(4))
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(6))
+				for i, result := range results {
+					if i%2 == 1 {
+						Expect(result.Err()).To(Equal(testError))
+					} else {
+						Expect(result.Unwrap()).To(Equal(i))
+					}
+				}
+			})
+		})
+	})
+
+	Describe("MapOk", func() {
+		Context("when transforming values with an infallible function", func() {
+			It("should behave like Map with a nil-error mapper", func() {
+				expected := op.Map(op.Range(1, 5), func(value int, _ int) (string, error) {
+					return fmt.Sprintf("item-%d", value), nil
+				})
+				out := op.MapOk(op.Range(1, 5), func(value int) string {
+					return fmt.Sprintf("item-%d", value)
+				})
+
+				expectedResults := make([]trx.Result[string], 0)
+				for result := range expected {
+					expectedResults = append(expectedResults, result)
+				}
+
+				results := make([]trx.Result[string], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(Equal(expectedResults))
+			})
+
+			It("should forward source errors", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(2)
+				close(source)
+
+				out := op.MapOk(source, func(value int) int { return value * 10 })
+
+				first := <-out
+				second := <-out
+				Expect(first.Err()).To(Equal(testError))
+				Expect(second.Unwrap()).To(Equal(20))
+			})
+		})
+	})
+
+	Describe("MapResult", func() {
+		Context("when the mapper returns Results", func() {
+			It("should emit Ok and Err results as returned", func() {
+				out := op.MapResult(op.FormSlice([]string{"1", "x", "3"}), func(value string, index int) trx.Result[int] {
+					n, err := strconv.Atoi(value)
+					if err != nil {
+						return trx.Err[int](err)
+					}
+
+					return trx.Ok(n * 10)
+				})
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(3))
+				Expect(results[0].Unwrap()).To(Equal(10))
+				Expect(results[1].IsErr()).To(BeTrue())
+				Expect(results[2].Unwrap()).To(Equal(30))
+			})
+		})
+	})
+
+	Describe("MapValidated", func() {
+		Context("when validation rejects some mapped values", func() {
+			It("should emit errors in place of the rejected values", func() {
+				tooLarge := errors.New("too large")
+				out := op.MapValidated(op.Range(1, 5), func(v int, _ int) (int, error) {
+					return v * 10, nil
+				}, func(v int) error {
+					if v > 30 {
+						return tooLarge
+					}
+
+					return nil
+				}, op.WithSerialize())
+
+				values := make([]int, 0)
+				errs := make([]error, 0)
+				for result := range out {
+					if result.IsErr() {
+						errs = append(errs, result.Err())
+
+						continue
+					}
+					values = append(values, result.Unwrap())
+				}
+
+				Expect(values).To(Equal([]int{10, 20, 30}))
+				Expect(errs).To(Equal([]error{tooLarge, tooLarge}))
+			})
+		})
+
+		Context("when the mapper fails", func() {
+			It("should forward the mapper error without validating", func() {
+				mapperError := errors.New("mapper error")
+				validated := false
+
+				out := op.MapValidated(op.Range(0, 1), func(v int, _ int) (int, error) {
+					return 0, mapperError
+				}, func(v int) error {
+					validated = true
+
+					return nil
+				})
+
+				result := <-out
+				Expect(result.Err()).To(Equal(mapperError))
+				Expect(validated).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("MapFilter", func() {
+		Context("with WithEmitCancellationError", func() {
+			It("should not block when the consumer stopped reading before cancelling", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancelCause(context.Background())
+
+					out := op.MapFilter(op.Range(0, 10000, op.WithContext(ctx)), func(v int, _ int) (int, bool, error) {
+						return v, true, nil
+					}, op.WithPoolSize(8), op.WithContext(ctx), op.WithEmitCancellationError())
+
+					count := 0
+					for range out {
+						count++
+						if count == 5 {
+							break
+						}
+					}
+
+					cancel(errors.New("shutting down"))
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when mapping and skipping over a range", func() {
+			It("should emit only the kept mapped values", func() {
+				out := op.MapFilter(op.Range(0, 10), func(v int, _ int) (string, bool, error) {
+					return strconv.Itoa(v * v), v%3 == 0, nil
+				}, op.WithSerialize())
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"0", "9", "36", "81"}))
+			})
+		})
+
+		Context("when the mapper or the source fails", func() {
+			It("should emit the errors", func() {
+				testError := errors.New("source error")
+				mapperError := errors.New("mapper error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(3)
+				close(source)
+
+				out := op.MapFilter(source, func(v int, _ int) (int, bool, error) {
+					if v == 3 {
+						return 0, false, mapperError
+					}
+
+					return v, false, nil
+				}, op.WithSerialize())
+
+				errs := make([]error, 0)
+				for result := range out {
+					errs = append(errs, result.Err())
+				}
+
+				Expect(errs).To(Equal([]error{testError, mapperError}))
+			})
+		})
+
+		Context("with WithItemRetry", func() {
+			It("should retry a failing item before deciding whether to keep it", func() {
+				var attempts atomic.Int32
+
+				out := op.MapFilter(op.Range(0, 3), func(v int, _ int) (int, bool, error) {
+					if v == 1 && attempts.Add(1) < 3 {
+						return 0, false, errors.New("flaky")
+					}
+
+					return v * 10, v > 0, nil
+				}, op.WithItemRetry(2, time.Millisecond))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{10, 20}))
+				Expect(attempts.Load()).To(Equal(int32(3)))
+			})
+		})
+	})
+
+	Describe("TryMap", func() {
+		Context("when the function panics for a value", func() {
+			It("should turn that item into an error and keep processing", func() {
+				out := op.TryMap(op.Range(1, 4), func(value int) int {
+					if value == 3 {
+						panic("cannot handle 3")
+					}
+
+					return value * 10
+				})
+
+				values := make([]int, 0)
+				errs := make([]error, 0)
+				for result := range out {
+					if result.IsErr() {
+						errs = append(errs, result.Err())
+
+						continue
+					}
+					values = append(values, result.Unwrap())
+				}
+
+				Expect(values).To(Equal([]int{10, 20, 40}))
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0].Error()).To(ContainSubstring("cannot handle 3"))
+			})
+		})
+	})
+
+	Describe("MapAsync", func() {
+		future := func(value int, delay time.Duration) <-chan trx.Result[int] {
+			result := make(chan trx.Result[int], 1)
+
+			go func() {
+				defer close(result)
+
+				time.Sleep(delay)
+				result <- trx.Ok(value * 10)
+			}()
+
+			return result
+		}
+
+		// Later values resolve first
+		mapper := func(value int, index int) <-chan trx.Result[int] {
+			return future(value, time.Duration(5-value)*10*time.Millisecond)
+		}
+
+		Context("when futures resolve out of order", func() {
+			It("should collect every result", func() {
+				out := op.MapAsync(op.Range(1, 4), mapper, op.WithPoolSize(4))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(ConsistOf(10, 20, 30, 40))
+			})
+
+			It("should preserve the source order when serialized", func() {
+				out := op.MapAsync(op.Range(1, 4), mapper, op.WithPoolSize(4), op.WithSerialize())
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{10, 20, 30, 40}))
+			})
+		})
+
+		Context("when a future closes without a value", func() {
+			It("should emit ErrNoElements", func() {
+				out := op.MapAsync(op.Range(0, 1), func(value int, index int) <-chan trx.Result[int] {
+					empty := make(chan trx.Result[int])
+					close(empty)
+
+					return empty
+				})
+
+				result := <-out
+				Expect(result.Err()).To(MatchError(op.ErrNoElements))
+			})
+		})
+	})
+
+	Describe("FlatMap", func() {
+		repeat := func(value int, index int) <-chan trx.Result[int] {
+			return op.FormSlice([]int{value, value})
+		}
+
+		Context("with the default pool size", func() {
+			It("should concatenate the inner channels", func() {
+				out := op.FlatMap(op.Range(1, 3), repeat)
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{1, 1, 2, 2, 3, 3}))
+			})
+		})
+
+		Context("when WithPoolSize is set", func() {
+			It("should merge the inner channels concurrently up to the pool size", func() {
+				var active, peak atomic.Int32
+
+				out := op.FlatMap(op.Range(0, 6), func(value int, index int) <-chan trx.Result[int] {
+					inner := make(chan trx.Result[int])
+
+					go func() {
+						defer close(inner)
+
+						n := active.Add(1)
+						for {
+							p := peak.Load()
+							if n <= p || peak.CompareAndSwap(p, n) {
+								break
+							}
+						}
+
+						time.Sleep(20 * time.Millisecond)
+						active.Add(-1)
+						inner <- trx.Ok(value)
+					}()
+
+					return inner
+				}, op.WithPoolSize(3))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(ConsistOf(0, 1, 2, 3, 4, 5))
+				Expect(peak.Load()).To(BeNumerically("<=", 3))
+				Expect(peak.Load()).To(BeNumerically(">", 1))
+			})
+		})
+
+		Context("when the source or an inner channel contains errors", func() {
+			It("should forward the errors downstream", func() {
+				errSource := errors.New("source error")
+				errInner := errors.New("inner error")
+
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](errSource)
+				source <- trx.Ok(2)
+				close(source)
+
+				out := op.FlatMap(source, func(value int, index int) <-chan trx.Result[int] {
+					inner := make(chan trx.Result[int], 2)
+					inner <- trx.Ok(value)
+					inner <- trx.Err[int](errInner)
+					close(inner)
+
+					return inner
+				})
+
+				var values []int
+				var errs []error
+				for result := range out {
+					if result.IsErr() {
+						errs = append(errs, result.Err())
+
+						continue
+					}
+
+					values = append(values, result.Unwrap())
+				}
+
+				Expect(values).To(Equal([]int{1, 2}))
+				Expect(errs).To(ConsistOf(errInner, errSource, errInner))
+			})
+
+			It("should keep flattening the other inner channels after an inner error", func() {
+				innerError := errors.New("inner error")
+
+				out := op.FlatMap(op.Range(0, 4), isolatedInner(innerError), op.WithPoolSize(3))
+
+				values, errs := collectIsolated(out)
+
+				Expect(values).To(ConsistOf(0, 1, 2, 10, 11, 12, 20, 21, 22, 30, 31, 32))
+				Expect(errs).To(Equal([]error{innerError}))
+			})
+		})
+
+		Context("when the context is cancelled", func() {
+			It("should stop without leaking goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					out := op.FlatMap(op.Range(0, 3), func(value int, index int) <-chan trx.Result[int] {
+						return op.Interval(time.Millisecond, op.WithContext(ctx))
+					}, op.WithPoolSize(2), op.WithContext(ctx))
+
+					<-out
+					cancel()
+
+					Eventually(out).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("ConcatMap", func() {
+		Context("when mapping to inner channels", func() {
+			It("should emit the inner channels one after another in source order", func() {
+				out := op.ConcatMap(op.Range(1, 3), func(value int, index int) <-chan trx.Result[int] {
+					return op.Range(value*10, 3)
+				}, op.WithPoolSize(4))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]int{10, 11, 12, 20, 21, 22, 30, 31, 32}))
+			})
+		})
+
+		Context("when an inner channel fails", func() {
+			It("should keep concatenating the following inner channels", func() {
+				innerError := errors.New("inner error")
+
+				values, errs := collectIsolated(op.ConcatMap(op.Range(0, 4), isolatedInner(innerError)))
+
+				Expect(values).To(Equal([]int{0, 1, 2, 10, 11, 12, 20, 21, 22, 30, 31, 32}))
+				Expect(errs).To(Equal([]error{innerError}))
+			})
+		})
+	})
+
+	Describe("SwitchMap", func() {
+		Context("when a new value arrives while an inner channel is active", func() {
+			It("should drop the results of the previous inner channel", func() {
+				source := make(chan trx.Result[string])
+				inners := map[string]chan trx.Result[string]{
+					"a":  make(chan trx.Result[string]),
+					"ab": make(chan trx.Result[string]),
+				}
+
+				out := op.SwitchMap(source, func(query string, _ int) <-chan trx.Result[string] {
+					return inners[query]
+				})
+
+				source <- trx.Ok("a")
+				inners["a"] <- trx.Ok("a:1")
+				first := <-out
+				Expect(first.Unwrap()).To(Equal("a:1"))
+
+				source <- trx.Ok("ab")
+				// The stale result is drained instead of blocking its producer.
+				inners["a"] <- trx.Ok("a:2")
+				close(inners["a"])
+
+				inners["ab"] <- trx.Ok("ab:1")
+				close(inners["ab"])
+				close(source)
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"ab:1"}))
+			})
+		})
+
+		Context("when the source closes while the last inner channel is active", func() {
+			It("should wait for the last inner channel to complete", func() {
+				inner := make(chan trx.Result[int])
+
+				out := op.SwitchMap(op.Just(1), func(value int, _ int) <-chan trx.Result[int] {
+					return inner
+				})
+
+				Consistently(out, 50*time.Millisecond).ShouldNot(BeClosed())
+
+				inner <- trx.Ok(10)
+				result := <-out
+				Expect(result.Unwrap()).To(Equal(10))
+
+				close(inner)
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should forward the error downstream", func() {
+				sourceError := errors.New("source error")
+
+				source := make(chan trx.Result[int], 1)
+				source <- trx.Err[int](sourceError)
+				close(source)
+
+				out := op.SwitchMap(source, func(value int, _ int) <-chan trx.Result[int] {
+					return op.Just(value)
+				})
+
+				result := <-out
+				Expect(result.Err()).To(Equal(sourceError))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the context is cancelled", func() {
+			It("should stop without leaking goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					out := op.SwitchMap(op.Range(0, 3), func(value int, _ int) <-chan trx.Result[int] {
+						return op.Interval(time.Millisecond, op.WithContext(ctx))
+					}, op.WithContext(ctx))
+
+					<-out
+					cancel()
+
+					Eventually(out).Should(BeClosed())
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("SwitchMapContext", func() {
+		Context("when switching between endless inner channels", func() {
+			It("should stop every abandoned inner channel without leaking goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					source := make(chan trx.Result[int])
+
+					out := op.SwitchMapContext(source, func(ctx context.Context, value int, _ int) <-chan trx.Result[int] {
+						if value < 0 {
+							return op.Just(value)
+						}
+
+						return op.MapOk(op.Interval(time.Millisecond, op.WithContext(ctx)), func(int) int {
+							return value
+						}, op.WithContext(ctx))
+					})
+
+					for value := range 3 {
+						source <- trx.Ok(value)
+
+						// Wait until the new inner channel is the one being relayed.
+						for {
+							result := <-out
+							if result.Unwrap() == value {
+								break
+							}
+						}
+					}
+
+					source <- trx.Ok(-1)
+					close(source)
+
+					results := make([]int, 0)
+					for result := range out {
+						results = append(results, result.Unwrap())
+					}
+
+					Expect(results).To(ContainElement(-1))
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("MapResizable", func() {
+		Context("when resizing the pool mid-stream", func() {
+			It("should map every value and change the concurrency", func() {
+				var (
+					mu         sync.Mutex
+					current    int
+					maxCurrent int
+				)
+
+				mapper := func(value int, index int) (int, error) {
+					mu.Lock()
+					current++
+					maxCurrent = max(maxCurrent, current)
+					mu.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					mu.Lock()
+					current--
+					mu.Unlock()
+
+					return value * 2, nil
+				}
+
+				out, resize := op.MapResizable(op.Range(0, 30), mapper, op.WithPoolSize(1))
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+
+					if len(results) == 5 {
+						mu.Lock()
+						Expect(maxCurrent).To(Equal(1))
+						maxCurrent = 0
+						mu.Unlock()
+
+						resize(4)
+					}
+				}
+
+				expected := make([]int, 30)
+				for i := range expected {
+					expected[i] = i * 2
+				}
+				Expect(results).To(ConsistOf(expected))
+
+				mu.Lock()
+				defer mu.Unlock()
+				Expect(maxCurrent).To(BeNumerically(">", 1))
+				Expect(maxCurrent).To(BeNumerically("<=", 4))
+			})
+		})
+
+		Context("when the options slice has spare capacity", func() {
+			It("should not write into the caller's slice", func() {
+				options := make([]op.Option, 1, 4)
+				options[0] = op.WithPoolSize(2)
+
+				out, _ := op.MapResizable(op.Range(0, 3), func(value int, index int) (int, error) {
+					return value, nil
+				}, options...)
+
+				for range out {
+				}
+
+				Expect(options[:2][1]).To(BeNil())
+			})
+		})
+	})
+
+	Describe("MapWithDeadLetter", func() {
+		Context("when some inputs fail to map", func() {
+			It("should route the failed inputs to the dead-letter channel", func() {
+				oddError := errors.New("odd value")
+				out, failed := op.MapWithDeadLetter(op.Range(0, 6), func(v int, _ int) (string, error) {
+					if v%2 == 1 {
+						return "", oddError
+					}
+
+					return strconv.Itoa(v), nil
+				})
+
+				var letters []trx.DeadLetter[int]
+				lettersDone := make(chan struct{})
+				go func() {
+					defer close(lettersDone)
+
+					for letter := range failed {
+						letters = append(letters, letter)
+					}
+				}()
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+				Eventually(lettersDone).Should(BeClosed())
+
+				Expect(results).To(ConsistOf("0", "2", "4"))
+				Expect(letters).To(ConsistOf(
+					trx.DeadLetter[int]{Value: 1, Err: oddError},
+					trx.DeadLetter[int]{Value: 3, Err: oddError},
+					trx.DeadLetter[int]{Value: 5, Err: oddError},
+				))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should forward it on the main output", func() {
+				testError := errors.New("source error")
+				out, failed := op.MapWithDeadLetter(op.Throw[int](testError), func(v int, _ int) (int, error) {
+					return v, nil
+				})
+
+				result := <-out
+				Expect(result.Err()).To(Equal(testError))
+				Eventually(failed).Should(BeClosed())
+			})
+		})
+
+		Context("when the context is cancelled while neither output is read", func() {
+			It("should not leak goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					var mapped atomic.Int64
+					// Neither output is ever read
+					op.MapWithDeadLetter(op.Range(0, 100, op.WithContext(ctx)), func(v int, _ int) (int, error) {
+						mapped.Add(1)
+						if v%2 == 1 {
+							return 0, errors.New("odd value")
+						}
+
+						return v, nil
+					}, op.WithContext(ctx))
+
+					Eventually(mapped.Load).Should(BeNumerically(">=", 2))
+					cancel()
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("MapWithProgress", func() {
+		Context("when processing a known number of items", func() {
+			It("should report progress reaching 1.0 after all items", func() {
+				out, progress := op.MapWithProgress(op.Range(0, 8), 8, func(value int, index int) (int, error) {
+					return value * 2, nil
+				})
+
+				reports := make([]float64, 0)
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+
+					for p := range progress {
+						reports = append(reports, p)
+					}
+				}()
+
+				results := make([]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+				<-done
+
+				Expect(results).To(Equal([]int{0, 2, 4, 6, 8, 10, 12, 14}))
+				Expect(reports).NotTo(BeEmpty())
+				Expect(reports[len(reports)-1]).To(Equal(1.0))
+				Expect(sort.Float64sAreSorted(reports)).To(BeTrue())
+			})
+
+			It("should not block when progress is not read", func() {
+				out, progress := op.MapWithProgress(op.Range(0, 5), 5, func(value int, index int) (int, error) {
+					return value, nil
+				})
+
+				count := 0
+				for range out {
+					count++
+				}
+
+				Expect(count).To(Equal(5))
+				Expect(<-progress).To(Equal(1.0))
+				Eventually(progress).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("BufferWithCount", func() {
+		Context("when buffering values by count", func() {
+			It("should group values into batches of specified size", func() {
+				source := op.Range(0, 7) // [0, 1, 2, 3, 4, 5, 6]
+				out := op.BufferWithCount(source, 3)
+
+				expectedBatches := [][]int{
+					{0, 1, 2},
+					{3, 4, 5},
+					{6}, // Last batch with remaining items
+				}
+
+				results := make([][]int, 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+
+					batch, err := result.Get()
+					Expect(err).To(BeNil())
+					results = append(results, batch)
+				}
+
+				Expect(results).To(Equal(expectedBatches))
+			})
+
+			It("should handle exact multiples of buffer size", func() {
+				source := op.Range(0, 6) // [0, 1, 2, 3, 4, 5]
+				out := op.BufferWithCount(source, 2)
+
+				expectedBatches := [][]int{
+					{0, 1},
+					{2, 3},
+					{4, 5},
+				}
+
+				results := make([][]int, 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+
+					batch, err := result.Get()
+					Expect(err).To(BeNil())
+					results = append(results, batch)
+				}
+
+				Expect(results).To(Equal(expectedBatches))
+			})
+
+			It("should handle buffer size of 1", func() {
+				source := op.Range(0, 3)
+				out := op.BufferWithCount(source, 1)
+
+				expectedBatches := [][]int{
+					{0}, {1}, {2},
+				}
+
+				results := make([][]int, 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+
+					batch, err := result.Get()
+					Expect(err).To(BeNil())
+					results = append(results, batch)
+				}
+
+				Expect(results).To(Equal(expectedBatches))
+			})
+
+			It("should handle empty sources", func() {
+				source := op.Range(0, 0)
+				out := op.BufferWithCount(source, 3)
+
+				count := 0
+				for range out {
+					count++
+				}
+
+				Expect(count).To(Equal(0))
+			})
+
+			It("should work with different data types", func() {
+				source := op.FormSlice([]string{"a", "b", "c", "d", "e"})
+				out := op.BufferWithCount(source, 2)
+
+				expectedBatches := [][]string{
+					{"a", "b"},
+					{"c", "d"},
+					{"e"},
+				}
+
+				results := make([][]string, 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+
+					batch, err := result.Get()
+					Expect(err).To(BeNil())
+					results = append(results, batch)
+				}
+
+				Expect(results).To(Equal(expectedBatches))
+			})
+		})
+
+		Context("when copying batches", func() {
+			It("should not share memory between a mutated batch and later batches", func() {
+				out := op.BufferWithCount(op.Range(0, 5), 2, op.WithCopyBatches())
+
+				first := <-out
+				batch := first.Unwrap()
+				batch[0] = 100
+				batch = append(batch, 200)
+
+				results := make([][]int, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(batch).To(Equal([]int{100, 1, 200}))
+				Expect(results).To(Equal([][]int{{2, 3}, {4}}))
+			})
+
+			It("should emit batches with no spare capacity", func() {
+				out := op.BufferWithCount(op.Range(0, 3), 2, op.WithCopyBatches())
+
+				for result := range out {
+					batch := result.Unwrap()
+					Expect(cap(batch)).To(Equal(len(batch)))
+				}
+			})
+		})
 	})
 
-	Describe("BufferWithCount", func() {
-		Context("when buffering values by count", func() {
-			It("should group values into batches of specified size", func() {
-				source := op.Range(0, 7) // [0, 1, 2, 3, 4, 5, 6]
-				out := op.BufferWithCount(source, 3)
+	Describe("BufferUntilCommit", func() {
+		Context("when commits pace the flushing", func() {
+			It("should emit pending items on each commit and stop reading at the cap", func() {
+				source := make(chan trx.Result[int], 10)
+				for i := 0; i < 10; i++ {
+					source <- trx.Ok(i)
+				}
+				close(source)
 
-				expectedBatches := [][]int{
-					{0, 1, 2},
-					{3, 4, 5},
-					{6}, // Last batch with remaining items
+				commit := make(chan struct{})
+				out := op.BufferUntilCommit(source, commit, 3)
+
+				// The buffer fills up to the cap, then the source is no longer read
+				Eventually(func() int { return len(source) }).Should(Equal(7))
+				Consistently(func() int { return len(source) }, 50*time.Millisecond).Should(Equal(7))
+
+				commit <- struct{}{}
+				first := <-out
+				Expect(first.Unwrap()).To(Equal([]int{0, 1, 2}))
+				Eventually(func() int { return len(source) }).Should(Equal(4))
+
+				commit <- struct{}{}
+				second := <-out
+				Expect(second.Unwrap()).To(Equal([]int{3, 4, 5}))
+
+				commit <- struct{}{}
+				third := <-out
+				Expect(third.Unwrap()).To(Equal([]int{6, 7, 8}))
+
+				// The source closes with one item left, which is flushed without a commit
+				last := <-out
+				Expect(last.Unwrap()).To(Equal([]int{9}))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the commit channel closes", func() {
+			It("should flush pending items and complete", func() {
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+
+				commit := make(chan struct{})
+				out := op.BufferUntilCommit(source, commit, 5)
+
+				Eventually(func() int { return len(source) }).Should(Equal(0))
+				close(commit)
+
+				result := <-out
+				Expect(result.Unwrap()).To(Equal([]int{1, 2}))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should emit the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				close(source)
+
+				out := op.BufferUntilCommit(source, make(chan struct{}), 5)
+
+				result := <-out
+				Expect(result.Err()).To(Equal(testError))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("BufferWithTime", func() {
+		Context("when buffering values by time", func() {
+			It("should emit batches after timeout", func() {
+				source := make(chan trx.Result[int], 5)
+
+				// Send values with delays
+				go func() {
+					defer close(source)
+					source <- trx.Ok(1)
+					source <- trx.Ok(2)
+					source <- trx.Ok(3)
+					time.Sleep(60 * time.Millisecond) // Force timeout
+					source <- trx.Ok(4)
+					source <- trx.Ok(5)
+				}()
+
+				out := op.BufferWithTime(op.FormChannel(source), 50*time.Millisecond, 0)
+
+				batches := make([][]trx.Result[int], 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+
+					batch, err := result.Get()
+					Expect(err).To(BeNil())
+					batches = append(batches, batch)
+				}
+
+				// Should have at least 2 batches due to timeout
+				Expect(len(batches)).To(BeNumerically(">=", 2))
+
+				// Check first batch content
+				if len(batches) > 0 {
+					firstBatch := make([]int, 0)
+					for _, res := range batches[0] {
+						if res.IsOk() {
+							val, _ := res.Get()
+							firstBatch = append(firstBatch, val)
+						}
+					}
+					Expect(firstBatch).To(Equal([]int{1, 2, 3}))
+				}
+			})
+
+			It("should respect max size limit", func() {
+				source := make(chan trx.Result[int], 10)
+
+				// Send many values quickly
+				go func() {
+					defer close(source)
+					for i := 1; i <= 8; i++ {
+						source <- trx.Ok(i)
+					}
+				}()
+
+				out := op.BufferWithTime(op.FormChannel(source), 100*time.Millisecond, 3)
+
+				batches := make([][]trx.Result[int], 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+
+					batch, err := result.Get()
+					Expect(err).To(BeNil())
+					batches = append(batches, batch)
+				}
+
+				// Most batches should be limited by max size
+				for i, batch := range batches[:len(batches)-1] { // Exclude last batch which might be partial
+					Expect(len(batch)).To(BeNumerically("<=", 3), fmt.Sprintf("Batch %d exceeded max size", i))
+				}
+			})
+		})
+
+		Context("when aligning windows to the clock", func() {
+			It("should flush the first window at the next aligned boundary", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 12, 0, 42, 0, time.UTC))
+				source := make(chan trx.Result[int])
+
+				out := op.BufferWithTime(source, time.Minute, 0, op.WithAlignToClock(), op.WithClock(clock))
+
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+
+				Eventually(clock.Waits).Should(Equal([]time.Duration{18 * time.Second}))
+				Consistently(out, 20*time.Millisecond).ShouldNot(Receive())
+
+				clock.Advance(18 * time.Second)
+
+				var batch trx.Result[[]int]
+				Eventually(out).Should(Receive(&batch))
+				Expect(batch.Unwrap()).To(Equal([]int{1, 2}))
+
+				source <- trx.Ok(3)
+				close(source)
+
+				Eventually(out).Should(Receive(&batch))
+				Expect(batch.Unwrap()).To(Equal([]int{3}))
+				Eventually(out).Should(BeClosed())
+			})
+
+			It("should keep later windows on the boundaries after a size flush", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 12, 0, 42, 0, time.UTC))
+				source := make(chan trx.Result[int])
+
+				out := op.BufferWithTime(source, time.Minute, 3, op.WithAlignToClock(), op.WithClock(clock))
+
+				source <- trx.Ok(1)
+				Eventually(clock.Waits).Should(Equal([]time.Duration{18 * time.Second}))
+				clock.Advance(18 * time.Second)
+
+				var batch trx.Result[[]int]
+				Eventually(out).Should(Receive(&batch))
+				Expect(batch.Unwrap()).To(Equal([]int{1}))
+				Eventually(clock.Waits).Should(Equal([]time.Duration{18 * time.Second, time.Minute}))
+
+				// A size flush in the middle of the second window must not move its end.
+				clock.Advance(20 * time.Second)
+				go func() {
+					for _, v := range []int{2, 3, 4} {
+						source <- trx.Ok(v)
+					}
+				}()
+
+				Eventually(out).Should(Receive(&batch))
+				Expect(batch.Unwrap()).To(Equal([]int{2, 3, 4}))
+
+				source <- trx.Ok(5)
+				Consistently(out, 20*time.Millisecond).ShouldNot(Receive())
+
+				clock.Advance(40 * time.Second)
+				Eventually(out).Should(Receive(&batch))
+				Expect(batch.Unwrap()).To(Equal([]int{5}))
+				Expect(clock.Now()).To(Equal(time.Date(2025, 1, 1, 12, 2, 0, 0, time.UTC)))
+				Eventually(clock.Waits).Should(Equal([]time.Duration{18 * time.Second, time.Minute, time.Minute}))
+
+				close(source)
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("BufferWithTimeStamped", func() {
+		t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		Context("when windows are driven by the clock", func() {
+			It("should emit contiguous windows of length d", func() {
+				clock := newFakeClock(t0)
+				source := make(chan trx.Result[int])
+
+				out := op.BufferWithTimeStamped(source, time.Second, 0, op.WithClock(clock))
+
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				clock.Advance(time.Second)
+
+				var first trx.Result[trx.TimedBatch[int]]
+				Eventually(out).Should(Receive(&first))
+
+				Eventually(clock.Waits).Should(HaveLen(2))
+				source <- trx.Ok(3)
+				clock.Advance(time.Second)
+
+				var second trx.Result[trx.TimedBatch[int]]
+				Eventually(out).Should(Receive(&second))
+
+				a, b := first.Unwrap(), second.Unwrap()
+				Expect(a.Items).To(Equal([]int{1, 2}))
+				Expect(b.Items).To(Equal([]int{3}))
+				Expect(a.Start).To(Equal(t0))
+				Expect(a.End.Sub(a.Start)).To(Equal(time.Second))
+				Expect(b.Start).To(Equal(a.End))
+				Expect(b.End.Sub(b.Start)).To(Equal(time.Second))
+
+				close(source)
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when a window reaches maxSize", func() {
+			It("should close the window early and start the next one there", func() {
+				clock := newFakeClock(t0)
+				source := make(chan trx.Result[int])
+
+				out := op.BufferWithTimeStamped(source, time.Minute, 2, op.WithClock(clock))
+
+				source <- trx.Ok(1)
+				clock.Advance(10 * time.Second)
+				source <- trx.Ok(2)
+
+				var full trx.Result[trx.TimedBatch[int]]
+				Eventually(out).Should(Receive(&full))
+
+				source <- trx.Ok(3)
+				close(source)
+
+				var rest trx.Result[trx.TimedBatch[int]]
+				Eventually(out).Should(Receive(&rest))
+
+				a, b := full.Unwrap(), rest.Unwrap()
+				Expect(a.Items).To(Equal([]int{1, 2}))
+				Expect(a.Start).To(Equal(t0))
+				Expect(a.End).To(Equal(t0.Add(10 * time.Second)))
+				Expect(b.Items).To(Equal([]int{3}))
+				Expect(b.Start).To(Equal(a.End))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("BufferWithTimeOrCount", func() {
+		Context("when buffering values by time or count", func() {
+			It("should emit when count is reached", func() {
+				source := op.Range(0, 8)
+				out := op.BufferWithTimeOrCount(source, 100*time.Millisecond, 3)
+
+				batches := make([][]int, 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+
+					batch, err := result.Get()
+					Expect(err).To(BeNil())
+					batches = append(batches, batch)
+				}
+
+				// Most batches should have exactly 3 items (count limit)
+				for i, batch := range batches[:len(batches)-1] {
+					Expect(len(batch)).To(Equal(3), fmt.Sprintf("Batch %d should have 3 items", i))
+				}
+
+				// Last batch should have remaining items
+				if len(batches) > 0 {
+					lastBatch := batches[len(batches)-1]
+					Expect(len(lastBatch)).To(BeNumerically("<=", 3))
+				}
+			})
+
+			It("should emit when time expires", func() {
+				source := make(chan trx.Result[int], 10)
+
+				// Send some values then wait
+				go func() {
+					defer close(source)
+					source <- trx.Ok(1)
+					source <- trx.Ok(2)
+					time.Sleep(60 * time.Millisecond) // Force timeout
+					source <- trx.Ok(3)
+					source <- trx.Ok(4)
+				}()
+
+				out := op.BufferWithTimeOrCount(op.FormChannel(source), 50*time.Millisecond, 5)
+
+				batches := make([][]trx.Result[int], 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+
+					batch, err := result.Get()
+					Expect(err).To(BeNil())
+					batches = append(batches, batch)
+				}
+
+				// Should have multiple batches due to timeout
+				Expect(len(batches)).To(BeNumerically(">=", 2))
+			})
+		})
+	})
+
+	Describe("BufferAdaptive", func() {
+		Context("when driven by a fake clock", func() {
+			It("should hold, flush on target count and cap batches at max time", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				source := make(chan trx.Result[int])
+				out := op.BufferAdaptive(source, time.Second, 5*time.Second, 3, op.WithClock(clock), op.WithContext(ctx))
+
+				// The target count is reached before minTime: the batch is held
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				source <- trx.Ok(3)
+				Eventually(clock.Waits).Should(HaveLen(2))
+				Consistently(out, 50*time.Millisecond).ShouldNot(Receive())
+
+				clock.Advance(time.Second)
+				first := <-out
+				Expect(first.Unwrap()).To(Equal([]int{1, 2, 3}))
+
+				// Once minTime has elapsed, reaching the target count flushes right away
+				source <- trx.Ok(4)
+				Eventually(clock.Waits).Should(HaveLen(4))
+				clock.Advance(time.Second)
+				source <- trx.Ok(5)
+				source <- trx.Ok(6)
+
+				second := <-out
+				Expect(second.Unwrap()).To(Equal([]int{4, 5, 6}))
+
+				// A small batch is capped by maxTime
+				source <- trx.Ok(7)
+				Eventually(clock.Waits).Should(HaveLen(6))
+				clock.Advance(5 * time.Second)
+
+				third := <-out
+				Expect(third.Unwrap()).To(Equal([]int{7}))
+
+				close(source)
+				Eventually(out).Should(BeClosed())
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should emit the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				close(source)
+
+				out := op.BufferAdaptive(source, time.Hour, time.Hour, 10)
+
+				result := <-out
+				Expect(result.Err()).To(Equal(testError))
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("MapMulti", func() {
+		Context("when inputs emit different numbers of values", func() {
+			It("should flatten the emitted values in order", func() {
+				out := op.MapMulti(op.FormSlice([]int{0, 1, 3, 0, 2}), func(v int, _ int, emit func(string)) error {
+					for i := 0; i < v; i++ {
+						emit(strconv.Itoa(v))
+					}
+
+					return nil
+				})
+
+				results := make([]string, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
+				}
+
+				Expect(results).To(Equal([]string{"1", "3", "3", "3", "2", "2"}))
+			})
+		})
+
+		Context("when the mapper fails", func() {
+			It("should keep values emitted before the error and continue", func() {
+				mapperError := errors.New("mapper error")
+				out := op.MapMulti(op.Range(0, 3), func(v int, index int, emit func(int)) error {
+					emit(index * 10)
+					if v == 1 {
+						return mapperError
+					}
+
+					return nil
+				})
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(4))
+				Expect(results[0].Unwrap()).To(Equal(0))
+				Expect(results[1].Unwrap()).To(Equal(10))
+				Expect(results[2].Err()).To(Equal(mapperError))
+				Expect(results[3].Unwrap()).To(Equal(20))
+			})
+		})
+	})
+
+	Describe("MapBatched", func() {
+		Context("when mapping values in batches", func() {
+			It("should pass whole batches to the mapper and emit elements individually", func() {
+				source := op.Range(1, 5)
+				batches := make([][]int, 0)
+
+				out := op.MapBatched(source, 2, func(batch []int) ([]int, error) {
+					batches = append(batches, batch)
+
+					doubled := make([]int, len(batch))
+					for i, v := range batch {
+						doubled[i] = v * 2
+					}
+
+					return doubled, nil
+				})
+
+				results := make([]int, 0)
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+					results = append(results, result.Unwrap())
 				}
 
-				results := make([][]int, 0)
+				Expect(results).To(Equal([]int{2, 4, 6, 8, 10}))
+				Expect(batches).To(Equal([][]int{{1, 2}, {3, 4}, {5}}))
+			})
+
+			It("should allow the output count to differ from the input count", func() {
+				source := op.Range(0, 4)
+				out := op.MapBatched(source, 2, func(batch []int) ([]string, error) {
+					return []string{fmt.Sprintf("%v", batch)}, nil
+				})
+
+				results := make([]string, 0)
 				for result := range out {
-					Expect(result.IsOk()).To(BeTrue())
+					results = append(results, result.Unwrap())
+				}
 
-					batch, err := result.Get()
-					Expect(err).To(BeNil())
-					results = append(results, batch)
+				Expect(results).To(Equal([]string{"[0 1]", "[2 3]"}))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should abort the current batch and propagate the error", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 4)
+				source <- trx.Ok(1)
+				source <- trx.Ok(2)
+				source <- trx.Ok(3)
+				source <- trx.Err[int](testError)
+				close(source)
+
+				out := op.MapBatched(source, 2, func(batch []int) ([]int, error) {
+					return batch, nil
+				})
+
+				results := make([]trx.Result[int], 0)
+				for result := range out {
+					results = append(results, result)
 				}
 
-				Expect(results).To(Equal(expectedBatches))
+				Expect(results).To(HaveLen(3))
+				Expect(results[0].Unwrap()).To(Equal(1))
+				Expect(results[1].Unwrap()).To(Equal(2))
+				Expect(results[2].Err()).To(Equal(testError))
 			})
+		})
+	})
 
-			It("should handle exact multiples of buffer size", func() {
-				source := op.Range(0, 6) // [0, 1, 2, 3, 4, 5]
-				out := op.BufferWithCount(source, 2)
+	Describe("StateMachine", func() {
+		type tokenizerState struct {
+			word  string
+			quote bool
+		}
+
+		tokenize := func(state tokenizerState, c string) (tokenizerState, []string, error) {
+			switch {
+			case c == `"`:
+				if state.quote {
+					return tokenizerState{}, []string{state.word}, nil
+				}
 
-				expectedBatches := [][]int{
-					{0, 1},
-					{2, 3},
-					{4, 5},
+				return tokenizerState{quote: true}, nil, nil
+			case c == " " && !state.quote:
+				if state.word == "" {
+					return state, nil, nil
 				}
 
-				results := make([][]int, 0)
+				return tokenizerState{}, []string{state.word}, nil
+			case c == "!":
+				return state, nil, errors.New("unexpected character")
+			default:
+				state.word += c
+
+				return state, nil, nil
+			}
+		}
+
+		chars := func(input string) <-chan trx.Result[string] {
+			return op.FormSlice(strings.Split(input, ""))
+		}
+
+		Context("when running a tokenizer", func() {
+			It("should emit zero or more outputs per input", func() {
+				out := op.StateMachine(chars(`ab  "c d" e `), tokenizerState{}, tokenize)
+
+				results := make([]string, 0)
 				for result := range out {
 					Expect(result.IsOk()).To(BeTrue())
-
-					batch, err := result.Get()
-					Expect(err).To(BeNil())
-					results = append(results, batch)
+					results = append(results, result.Unwrap())
 				}
 
-				Expect(results).To(Equal(expectedBatches))
+				Expect(results).To(Equal([]string{"ab", "c d", "e"}))
 			})
+		})
 
-			It("should handle buffer size of 1", func() {
-				source := op.Range(0, 3)
-				out := op.BufferWithCount(source, 1)
+		Context("when the transition returns an error", func() {
+			It("should propagate the error and stop", func() {
+				out := op.StateMachine(chars("ab !cd "), tokenizerState{}, tokenize)
 
-				expectedBatches := [][]int{
-					{0}, {1}, {2},
+				results := make([]trx.Result[string], 0)
+				for result := range out {
+					results = append(results, result)
 				}
 
-				results := make([][]int, 0)
+				Expect(results).To(HaveLen(2))
+				Expect(results[0].Unwrap()).To(Equal("ab"))
+				Expect(results[1].IsErr()).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("Debatch", func() {
+		Context("when flattening several batches", func() {
+			It("should attach contiguous indices across batches", func() {
+				out := op.Debatch(op.FormSlice([][]string{{"a", "b"}, {}, {"c", "d", "e"}}))
+
+				results := make([]trx.Indexed[string], 0)
 				for result := range out {
 					Expect(result.IsOk()).To(BeTrue())
+					results = append(results, result.Unwrap())
+				}
 
-					batch, err := result.Get()
-					Expect(err).To(BeNil())
-					results = append(results, batch)
+				Expect(results).To(Equal([]trx.Indexed[string]{
+					{Index: 0, Value: "a"},
+					{Index: 1, Value: "b"},
+					{Index: 2, Value: "c"},
+					{Index: 3, Value: "d"},
+					{Index: 4, Value: "e"},
+				}))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should forward the error without advancing the index", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[[]int], 3)
+				source <- trx.Ok([]int{10})
+				source <- trx.Err[[]int](testError)
+				source <- trx.Ok([]int{20})
+				close(source)
+
+				results := make([]trx.Result[trx.Indexed[int]], 0)
+				for result := range op.Debatch(source) {
+					results = append(results, result)
 				}
 
-				Expect(results).To(Equal(expectedBatches))
+				Expect(results).To(HaveLen(3))
+				Expect(results[1].Err()).To(Equal(testError))
+				Expect(results[2].Unwrap()).To(Equal(trx.Indexed[int]{Index: 1, Value: 20}))
 			})
+		})
+	})
 
-			It("should handle empty sources", func() {
-				source := op.Range(0, 0)
-				out := op.BufferWithCount(source, 3)
+	Describe("Rechunk", func() {
+		Context("when batches have irregular sizes", func() {
+			It("should emit uniform chunks and flush the remainder", func() {
+				source := make(chan trx.Result[[]int], 6)
+				source <- trx.Ok([]int{0, 1})
+				source <- trx.Ok([]int{2, 3, 4, 5, 6, 7, 8})
+				source <- trx.Ok([]int{})
+				source <- trx.Ok([]int{9})
+				source <- trx.Ok([]int{10, 11, 12, 13})
+				source <- trx.Ok([]int{14, 15})
+				close(source)
 
-				count := 0
-				for range out {
-					count++
+				results := make([][]int, 0)
+				for result := range op.Rechunk(source, 4) {
+					results = append(results, result.Unwrap())
 				}
 
-				Expect(count).To(Equal(0))
+				Expect(results).To(Equal([][]int{
+					{0, 1, 2, 3},
+					{4, 5, 6, 7},
+					{8, 9, 10, 11},
+					{12, 13, 14, 15},
+				}))
 			})
 
-			It("should work with different data types", func() {
-				source := op.FormSlice([]string{"a", "b", "c", "d", "e"})
-				out := op.BufferWithCount(source, 2)
-
-				expectedBatches := [][]string{
-					{"a", "b"},
-					{"c", "d"},
-					{"e"},
+			It("should emit a smaller final chunk", func() {
+				results := make([][]int, 0)
+				for result := range op.Rechunk(op.FormSlice([][]int{{1, 2, 3}, {4, 5}}), 2) {
+					results = append(results, result.Unwrap())
 				}
 
-				results := make([][]string, 0)
-				for result := range out {
-					Expect(result.IsOk()).To(BeTrue())
+				Expect(results).To(Equal([][]int{{1, 2}, {3, 4}, {5}}))
+			})
+		})
 
-					batch, err := result.Get()
-					Expect(err).To(BeNil())
-					results = append(results, batch)
+		Context("when the source contains an error", func() {
+			It("should emit the error and stop", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[[]int], 3)
+				source <- trx.Ok([]int{1, 2, 3})
+				source <- trx.Err[[]int](testError)
+				source <- trx.Ok([]int{4})
+				close(source)
+
+				results := make([]trx.Result[[]int], 0)
+				for result := range op.Rechunk(source, 2) {
+					results = append(results, result)
 				}
 
-				Expect(results).To(Equal(expectedBatches))
+				Expect(results).To(HaveLen(2))
+				Expect(results[0].Unwrap()).To(Equal([]int{1, 2}))
+				Expect(results[1].Err()).To(Equal(testError))
 			})
 		})
 	})
 
-	Describe("BufferWithTime", func() {
-		Context("when buffering values by time", func() {
-			It("should emit batches after timeout", func() {
-				source := make(chan trx.Result[int], 5)
+	Describe("Coalesce", func() {
+		type record struct {
+			Key    string
+			Amount int
+		}
 
-				// Send values with delays
-				go func() {
-					defer close(source)
-					source <- trx.Ok(1)
-					source <- trx.Ok(2)
-					source <- trx.Ok(3)
-					time.Sleep(60 * time.Millisecond) // Force timeout
-					source <- trx.Ok(4)
-					source <- trx.Ok(5)
-				}()
+		sameKey := func(a, b record) bool {
+			return a.Key == b.Key
+		}
 
-				out := op.BufferWithTime(op.FormChannel(source), 50*time.Millisecond, 0)
+		sum := func(a, b record) record {
+			return record{Key: a.Key, Amount: a.Amount + b.Amount}
+		}
 
-				batches := make([][]trx.Result[int], 0)
-				for result := range out {
-					Expect(result.IsOk()).To(BeTrue())
+		Context("when adjacent records share a key", func() {
+			It("should sum their amounts into a single record", func() {
+				out := op.Coalesce(op.FormSlice([]record{
+					{"a", 1}, {"a", 2}, {"b", 3}, {"a", 4}, {"a", 5}, {"a", 6}, {"c", 7},
+				}), sameKey, sum)
 
-					batch, err := result.Get()
-					Expect(err).To(BeNil())
-					batches = append(batches, batch)
+				results := make([]record, 0)
+				for result := range out {
+					results = append(results, result.Unwrap())
 				}
 
-				// Should have at least 2 batches due to timeout
-				Expect(len(batches)).To(BeNumerically(">=", 2))
-
-				// Check first batch content
-				if len(batches) > 0 {
-					firstBatch := make([]int, 0)
-					for _, res := range batches[0] {
-						if res.IsOk() {
-							val, _ := res.Get()
-							firstBatch = append(firstBatch, val)
-						}
-					}
-					Expect(firstBatch).To(Equal([]int{1, 2, 3}))
-				}
+				Expect(results).To(Equal([]record{{"a", 3}, {"b", 3}, {"a", 15}, {"c", 7}}))
 			})
+		})
 
-			It("should respect max size limit", func() {
-				source := make(chan trx.Result[int], 10)
+		Context("when the source contains an error", func() {
+			It("should flush the pending record and restart after the error", func() {
+				sourceError := errors.New("source error")
 
-				// Send many values quickly
-				go func() {
-					defer close(source)
-					for i := 1; i <= 8; i++ {
-						source <- trx.Ok(i)
-					}
-				}()
+				source := make(chan trx.Result[record], 4)
+				source <- trx.Ok(record{"a", 1})
+				source <- trx.Err[record](sourceError)
+				source <- trx.Ok(record{"a", 2})
+				source <- trx.Ok(record{"a", 3})
+				close(source)
 
-				out := op.BufferWithTime(op.FormChannel(source), 100*time.Millisecond, 3)
+				out := op.Coalesce(source, sameKey, sum)
 
-				batches := make([][]trx.Result[int], 0)
-				for result := range out {
-					Expect(result.IsOk()).To(BeTrue())
+				first := <-out
+				Expect(first.Unwrap()).To(Equal(record{"a", 1}))
 
-					batch, err := result.Get()
-					Expect(err).To(BeNil())
-					batches = append(batches, batch)
-				}
+				second := <-out
+				Expect(second.Err()).To(Equal(sourceError))
 
-				// Most batches should be limited by max size
-				for i, batch := range batches[:len(batches)-1] { // Exclude last batch which might be partial
-					Expect(len(batch)).To(BeNumerically("<=", 3), fmt.Sprintf("Batch %d exceeded max size", i))
-				}
+				third := <-out
+				Expect(third.Unwrap()).To(Equal(record{"a", 5}))
+
+				Eventually(out).Should(BeClosed())
 			})
 		})
-	})
 
-	Describe("BufferWithTimeOrCount", func() {
-		Context("when buffering values by time or count", func() {
-			It("should emit when count is reached", func() {
-				source := op.Range(0, 8)
-				out := op.BufferWithTimeOrCount(source, 100*time.Millisecond, 3)
+		Context("when the source is empty", func() {
+			It("should close without emitting", func() {
+				out := op.Coalesce(op.Empty[record](), sameKey, sum)
 
-				batches := make([][]int, 0)
-				for result := range out {
-					Expect(result.IsOk()).To(BeTrue())
+				Eventually(out).Should(BeClosed())
+			})
+		})
 
-					batch, err := result.Get()
-					Expect(err).To(BeNil())
-					batches = append(batches, batch)
-				}
+		Context("when the context is cancelled while the consumer has stopped reading", func() {
+			It("should stop without leaking goroutines", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
 
-				// Most batches should have exactly 3 items (count limit)
-				for i, batch := range batches[:len(batches)-1] {
-					Expect(len(batch)).To(Equal(3), fmt.Sprintf("Batch %d should have 3 items", i))
-				}
+					records := op.MapOk(op.Range(0, 100, op.WithContext(ctx)), func(v int) record {
+						return record{Key: strconv.Itoa(v), Amount: v}
+					}, op.WithContext(ctx))
+					out := op.Coalesce(records, sameKey, sum, op.WithBufferSize(1), op.WithContext(ctx))
 
-				// Last batch should have remaining items
-				if len(batches) > 0 {
-					lastBatch := batches[len(batches)-1]
-					Expect(len(lastBatch)).To(BeNumerically("<=", 3))
-				}
+					<-out
+					cancel() // The output is not read again
+				})
+
+				Expect(err).NotTo(HaveOccurred())
 			})
+		})
+	})
 
-			It("should emit when time expires", func() {
-				source := make(chan trx.Result[int], 10)
+	Describe("Shuffle", func() {
+		collect := func(out <-chan trx.Result[int]) []int {
+			results := make([]int, 0)
+			for result := range out {
+				results = append(results, result.Unwrap())
+			}
 
-				// Send some values then wait
-				go func() {
-					defer close(source)
-					source <- trx.Ok(1)
-					source <- trx.Ok(2)
-					time.Sleep(60 * time.Millisecond) // Force timeout
-					source <- trx.Ok(3)
-					source <- trx.Ok(4)
-				}()
+			return results
+		}
 
-				out := op.BufferWithTimeOrCount(op.FormChannel(source), 50*time.Millisecond, 5)
+		Context("when shuffling a sequence", func() {
+			It("should preserve the multiset of values", func() {
+				input := []int{1, 2, 2, 3, 4, 5, 5, 5, 6, 7, 8, 9}
+				results := collect(op.Shuffle(op.FormSlice(input), 4, 1))
 
-				batches := make([][]trx.Result[int], 0)
-				for result := range out {
-					Expect(result.IsOk()).To(BeTrue())
+				Expect(results).To(ConsistOf(input))
+				Expect(results).NotTo(Equal(input))
+			})
 
-					batch, err := result.Get()
-					Expect(err).To(BeNil())
-					batches = append(batches, batch)
+			It("should yield the same order for the same seed", func() {
+				first := collect(op.Shuffle(op.Range(0, 50), 10, 42))
+				second := collect(op.Shuffle(op.Range(0, 50), 10, 42))
+				other := collect(op.Shuffle(op.Range(0, 50), 10, 7))
+
+				Expect(first).To(Equal(second))
+				Expect(first).NotTo(Equal(other))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should forward the error and keep shuffling", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(2)
+				close(source)
+
+				errs := make([]error, 0)
+				values := make([]int, 0)
+				for result := range op.Shuffle(source, 5, 1) {
+					if result.IsErr() {
+						errs = append(errs, result.Err())
+
+						continue
+					}
+					values = append(values, result.Unwrap())
 				}
 
-				// Should have multiple batches due to timeout
-				Expect(len(batches)).To(BeNumerically(">=", 2))
+				Expect(errs).To(Equal([]error{testError}))
+				Expect(values).To(ConsistOf(1, 2))
 			})
 		})
 	})
@@ -418,4 +2567,249 @@ var _ = Describe("Transformation Operations", func() {
 			})
 		})
 	})
+
+	Describe("GroupByParallel", func() {
+		perKey := func(key int, values <-chan trx.Result[int]) <-chan trx.Result[trx.Pair[int, int]] {
+			return op.MapOk(values, func(v int) trx.Pair[int, int] {
+				time.Sleep(time.Duration(rand.IntN(500)) * time.Microsecond)
+
+				return trx.Pair[int, int]{Key: key, Value: v}
+			})
+		}
+
+		collect := func(out <-chan trx.Result[trx.Pair[int, int]]) map[int][]int {
+			partitions := make(map[int][]int)
+			for result := range out {
+				pair := result.Unwrap()
+				partitions[pair.Key] = append(partitions[pair.Key], pair.Value)
+			}
+
+			return partitions
+		}
+
+		Context("when partitions run in parallel", func() {
+			It("should preserve the order within each partition", func() {
+				keyOf := func(v int) int { return v % 3 }
+				out := op.GroupByParallel(op.Range(0, 60), keyOf, perKey, op.WithPoolSize(3))
+
+				partitions := collect(out)
+
+				Expect(partitions).To(HaveLen(3))
+				for key, values := range partitions {
+					Expect(values).To(HaveLen(20))
+					Expect(sort.IntsAreSorted(values)).To(BeTrue())
+					for _, v := range values {
+						Expect(keyOf(v)).To(Equal(key))
+					}
+				}
+			})
+		})
+
+		Context("when more keys arrive than the pool size allows", func() {
+			It("should keep a single sub-pipeline per key", func() {
+				started := make(map[int]int)
+				var mu sync.Mutex
+
+				out := op.GroupByParallel(op.Range(0, 30), func(v int) int { return v % 3 },
+					func(key int, values <-chan trx.Result[int]) <-chan trx.Result[trx.Pair[int, int]] {
+						mu.Lock()
+						started[key]++
+						mu.Unlock()
+
+						return perKey(key, values)
+					}, op.WithPoolSize(2))
+
+				partitions := collect(out)
+
+				Expect(partitions).To(HaveLen(3))
+				for _, values := range partitions {
+					Expect(values).To(HaveLen(10))
+					Expect(sort.IntsAreSorted(values)).To(BeTrue())
+				}
+				Expect(started).To(Equal(map[int]int{0: 1, 1: 1, 2: 1}))
+			})
+
+			It("should emit a single result per key from a stateful sub-pipeline", func() {
+				out := op.GroupByParallel(op.Range(0, 40), func(v int) int { return v % 4 },
+					func(key int, values <-chan trx.Result[int]) <-chan trx.Result[trx.Pair[int, int]] {
+						return op.Reduce(values, trx.Pair[int, int]{Key: key}, func(acc trx.Pair[int, int], v int, _ int) (trx.Pair[int, int], error) {
+							acc.Value += v
+
+							return acc, nil
+						})
+					})
+
+				sums := make(map[int][]int)
+				for result := range out {
+					pair := result.Unwrap()
+					sums[pair.Key] = append(sums[pair.Key], pair.Value)
+				}
+
+				Expect(sums).To(Equal(map[int][]int{0: {180}, 1: {190}, 2: {200}, 3: {210}}))
+			})
+
+			It("should never run more sub-pipelines at once than the pool size", func() {
+				var active, peak atomic.Int64
+
+				out := op.GroupByParallel(op.Range(0, 200), func(v int) int { return v % 10 },
+					func(key int, values <-chan trx.Result[int]) <-chan trx.Result[trx.Pair[int, int]] {
+						if n := active.Add(1); n > peak.Load() {
+							peak.Store(n)
+						}
+
+						results := make(chan trx.Result[trx.Pair[int, int]])
+						go func() {
+							defer close(results)
+							defer active.Add(-1)
+
+							for v := range values {
+								results <- trx.Ok(trx.Pair[int, int]{Key: key, Value: v.Unwrap()})
+							}
+						}()
+
+						return results
+					}, op.WithPoolSize(3))
+
+				partitions := collect(out)
+
+				Expect(partitions).To(HaveLen(10))
+				for _, values := range partitions {
+					Expect(values).To(HaveLen(20))
+					Expect(sort.IntsAreSorted(values)).To(BeTrue())
+				}
+				Expect(peak.Load()).To(BeNumerically("<=", 3))
+			})
+
+			It("should start a waiting key once a sub-pipeline completes early", func() {
+				out := op.GroupByParallel(op.Range(0, 40), func(v int) int { return v % 2 },
+					func(key int, values <-chan trx.Result[int]) <-chan trx.Result[trx.Pair[int, int]] {
+						return perKey(key, op.Take(values, 2))
+					})
+
+				Expect(collect(out)).To(Equal(map[int][]int{0: {0, 2}, 1: {1, 3}}))
+			})
+
+			It("should not leak goroutines when cancelled with waiting keys", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					out := op.GroupByParallel(op.Range(0, 1000, op.WithContext(ctx)), func(v int) int { return v % 5 }, perKey,
+						op.WithPoolSize(2), op.WithContext(ctx))
+
+					<-out
+					cancel()
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should forward the error", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(2)
+				close(source)
+
+				out := op.GroupByParallel(source, func(v int) int { return v }, perKey, op.WithPoolSize(2))
+
+				values := make([]int, 0)
+				errs := make([]error, 0)
+				for result := range out {
+					if result.IsErr() {
+						errs = append(errs, result.Err())
+
+						continue
+					}
+					values = append(values, result.Unwrap().Value)
+				}
+
+				Expect(values).To(ConsistOf(1, 2))
+				Expect(errs).To(Equal([]error{testError}))
+			})
+		})
+	})
 })
+
+// countingPool is an op.Pool that runs every task on its own goroutine and counts the submissions.
+type countingPool struct {
+	submitted atomic.Int64
+	wg        sync.WaitGroup
+}
+
+func (p *countingPool) Submit(task func()) {
+	p.submitted.Add(1)
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		task()
+	}()
+}
+
+func (p *countingPool) Wait() {
+	p.wg.Wait()
+}
+
+// semaphorePool is an op.Pool that runs at most size tasks at once, blocking Submit while it is full.
+type semaphorePool struct {
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newSemaphorePool(size int) *semaphorePool {
+	return &semaphorePool{slots: make(chan struct{}, size)}
+}
+
+func (p *semaphorePool) Submit(task func()) {
+	p.slots <- struct{}{}
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+		defer func() { <-p.slots }()
+
+		task()
+	}()
+}
+
+func (p *semaphorePool) Wait() {
+	p.wg.Wait()
+}
+
+// isolatedInner returns a projection emitting three values per source value, with err in the middle of the
+// inner channel of value 1.
+func isolatedInner(err error) func(value int, index int) <-chan trx.Result[int] {
+	return func(value int, index int) <-chan trx.Result[int] {
+		inner := make(chan trx.Result[int], 4)
+		for i := 0; i < 3; i++ {
+			inner <- trx.Ok(value*10 + i)
+			if value == 1 && i == 0 {
+				inner <- trx.Err[int](err)
+			}
+		}
+		close(inner)
+
+		return inner
+	}
+}
+
+// collectIsolated splits the results of out into values and errors.
+func collectIsolated(out <-chan trx.Result[int]) ([]int, []error) {
+	var values []int
+	var errs []error
+	for result := range out {
+		if result.IsErr() {
+			errs = append(errs, result.Err())
+
+			continue
+		}
+
+		values = append(values, result.Unwrap())
+	}
+
+	return values, errs
+}
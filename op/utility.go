@@ -0,0 +1,297 @@
+package op
+
+import (
+	"iter"
+	"sync/atomic"
+	"time"
+
+	"github.com/foreveralonet/trx"
+)
+
+// Trace forwards every result from the source channel unchanged while recording it in recorder as a
+// timestamped event: a value, an error, or the completion of the source. The recorded timeline can be
+// inspected or rendered as a marble diagram to debug or assert the temporal shape of a stream.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channel.
+//
+// Parameters:
+//
+//	source   - A receive-only channel of trx.Result[T] representing the input stream.
+//	recorder - The recorder that collects the events.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the same results as the source.
+//
+// Example usage:
+//
+//	recorder := trx.NewTraceRecorder()
+//	out := Trace(Take(Interval(time.Second), 3), recorder)
+//	// ... drain out ...
+//	fmt.Println(recorder.Marble(time.Second)) // "-01(2|)"
+func Trace[T any](source <-chan trx.Result[T], recorder *trx.TraceRecorder, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					recorder.RecordComplete()
+
+					return
+				}
+
+				value, err := v.Get()
+				if err != nil {
+					recorder.RecordError(err)
+				} else {
+					recorder.RecordValue(value)
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Inspect forwards every result from the source channel unchanged while incrementing counter once per
+// forwarded result, including errors. It is a lightweight, lock-free way to observe the throughput at any
+// point of a pipeline from outside, for example by sampling counter periodically.
+//
+// The function supports optional configuration via Option parameters, such as context control.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channel.
+//
+// Parameters:
+//
+//	source  - A receive-only channel of trx.Result[T] representing the input stream.
+//	counter - The counter incremented for each forwarded result.
+//	options
+//	    - WithBufferSize
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the same results as the source.
+//
+// Example usage:
+//
+//	var parsed atomic.Int64
+//	out := Inspect(Map(source, parse), &parsed)
+func Inspect[T any](source <-chan trx.Result[T], counter *atomic.Int64, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				counter.Add(1)
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Heartbeat forwards every result from the source channel and injects the beat value whenever the source has
+// been silent for the given duration, so downstream keep-alive logic keeps receiving something. The silence
+// timer restarts after every emission, whether it is a forwarded result or a beat, so no beats are injected
+// while values flow faster than the duration. The output channel is closed when the source is closed.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	every  - The period of silence after which a beat is injected.
+//	beat   - The value emitted as a heartbeat.
+//	options
+//	    - WithBufferSize
+//	    - WithClock
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[T] containing the source results interleaved with beats.
+//
+// Example usage:
+//
+//	out := Heartbeat(events, 30*time.Second, Event{Kind: "ping"})
+func Heartbeat[T any](source <-chan trx.Result[T], every time.Duration, beat T, options ...Option) <-chan trx.Result[T] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[T](conf)
+
+	go func() {
+		defer close(out)
+
+		for {
+			silence := conf.clock.After(every)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-silence:
+				select {
+				case <-ctx.Done():
+					return
+				case out <- trx.Ok(beat):
+				}
+			case v, ok := <-source:
+				if !ok {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// ToIterator returns an iterator over the results of the source channel, yielding each result as its value
+// and error, so a pipeline can be consumed with an idiomatic range-over-func loop. If the loop stops early,
+// the rest of the source is drained in the background, so the upstream operators are not left blocked on a
+// send; for a source that never completes, also cancel the upstream context so the drain can finish.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//
+// Returns:
+//
+//	An iter.Seq2 yielding the value and error of each result of the source.
+//
+// Example usage:
+//
+//	for v, err := range ToIterator(Map(source, mapper)) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    // use v
+//	}
+func ToIterator[T any](source <-chan trx.Result[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for v := range source {
+			if !yield(v.Get()) {
+				go drain(source)
+
+				return
+			}
+		}
+	}
+}
+
+// Drain consumes the entire source channel, discarding its values, and emits a single trx.DrainReport once
+// the source is closed, with the number of successful values and errors, the first error and the time it
+// took. It is a convenient terminal for pipelines whose side effects happen in earlier stages such as Map.
+// Errors do not stop the consumption. If the context is cancelled, no report is emitted.
+//
+// Type Parameters:
+//
+//	T - The type of values from the source channel.
+//
+// Parameters:
+//
+//	source - A receive-only channel of trx.Result[T] representing the input stream.
+//	options
+//	    - WithBufferSize
+//	    - WithClock
+//	    - WithContext
+//
+// Returns:
+//
+//	A receive-only channel of trx.Result[trx.DrainReport] that emits the report of the stream.
+//
+// Example usage:
+//
+//	res := <-Drain(Map(records, save))
+//	report := res.Unwrap()
+//	log.Printf("saved %d records, %d failed", report.Count, report.Errors)
+func Drain[T any](source <-chan trx.Result[T], options ...Option) <-chan trx.Result[trx.DrainReport] {
+	conf := parseOption(options...)
+	ctx := makeContext(conf)
+	out := makeResultChannel[trx.DrainReport](conf)
+
+	go func() {
+		defer close(out)
+
+		start := conf.clock.Now()
+		report := trx.DrainReport{}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-source:
+				if !ok {
+					report.Duration = conf.clock.Now().Sub(start)
+					out <- trx.Ok(report)
+
+					return
+				}
+
+				if err := v.Err(); err != nil {
+					if report.FirstError == nil {
+						report.FirstError = err
+					}
+					report.Errors++
+
+					continue
+				}
+
+				report.Count++
+			}
+		}
+	}()
+
+	return out
+}
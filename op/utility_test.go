@@ -0,0 +1,214 @@
+package op_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/foreveralonet/trx"
+	"github.com/foreveralonet/trx/op"
+	"github.com/foreveralonet/trx/trxtest"
+)
+
+var _ = Describe("Utility Operations", func() {
+
+	Describe("Trace", func() {
+		Context("when tracing an interval and Take pipeline", func() {
+			It("should record a timeline matching the stream", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				recorder := trx.NewTraceRecorderWithClock(clock.Now)
+				ticks := op.IntervalPrecise(50*time.Millisecond, op.WithContext(ctx), op.WithClock(clock))
+				out := op.Trace(op.Take(ticks, 3), recorder)
+
+				for n := 1; n <= 3; n++ {
+					Eventually(clock.Waits).Should(HaveLen(n))
+					clock.Advance(50 * time.Millisecond)
+
+					result := <-out
+					Expect(result.Unwrap()).To(Equal(n - 1))
+				}
+				Eventually(out).Should(BeClosed())
+
+				Expect(recorder.Marble(50 * time.Millisecond)).To(Equal("-01(2|)"))
+
+				kinds := make([]trx.TraceEventKind, 0)
+				for _, event := range recorder.Events() {
+					kinds = append(kinds, event.Kind)
+				}
+				Expect(kinds).To(Equal([]trx.TraceEventKind{
+					trx.TraceValue, trx.TraceValue, trx.TraceValue, trx.TraceComplete,
+				}))
+			})
+		})
+
+		Context("when the source contains an error", func() {
+			It("should record and forward the error", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				close(source)
+
+				recorder := trx.NewTraceRecorder()
+				results := make([]trx.Result[int], 0)
+				for result := range op.Trace(source, recorder) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(2))
+				Expect(results[1].Err()).To(Equal(testError))
+				Expect(recorder.Marble(time.Hour)).To(Equal("(1#|)"))
+			})
+		})
+	})
+
+	Describe("Inspect", func() {
+		Context("when counting a stage of a pipeline", func() {
+			It("should count every forwarded result", func() {
+				var counter atomic.Int64
+				out := op.Inspect(op.Range(0, 25), &counter)
+
+				count := 0
+				for result := range out {
+					Expect(result.IsOk()).To(BeTrue())
+					count++
+				}
+
+				Expect(count).To(Equal(25))
+				Expect(counter.Load()).To(Equal(int64(25)))
+			})
+		})
+	})
+
+	Describe("Heartbeat", func() {
+		Context("when the source is slow", func() {
+			It("should inject beats during idle gaps only", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				source := make(chan trx.Result[int])
+				out := op.Heartbeat(source, time.Second, -1, op.WithClock(clock))
+
+				// Idle gap: a beat is injected
+				Eventually(clock.Waits).Should(HaveLen(1))
+				clock.Advance(time.Second)
+				first := <-out
+				Expect(first.Unwrap()).To(Equal(-1))
+
+				// Values flow faster than the period: no beats
+				Eventually(clock.Waits).Should(HaveLen(2))
+				source <- trx.Ok(1)
+				value := <-out
+				Expect(value.Unwrap()).To(Equal(1))
+
+				Eventually(clock.Waits).Should(HaveLen(3))
+				clock.Advance(500 * time.Millisecond)
+				source <- trx.Ok(2)
+				next := <-out
+				Expect(next.Unwrap()).To(Equal(2))
+
+				Eventually(clock.Waits).Should(HaveLen(4))
+				clock.Advance(500 * time.Millisecond)
+				Consistently(out, 50*time.Millisecond).ShouldNot(Receive())
+
+				// The timer restarted with the last value
+				clock.Advance(500 * time.Millisecond)
+				second := <-out
+				Expect(second.Unwrap()).To(Equal(-1))
+
+				close(source)
+				Eventually(out).Should(BeClosed())
+			})
+		})
+	})
+
+	Describe("ToIterator", func() {
+		Context("when ranging over a pipeline", func() {
+			It("should yield each value and error", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 3)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				source <- trx.Ok(2)
+				close(source)
+
+				values := make([]int, 0)
+				errs := make([]error, 0)
+				for v, err := range op.ToIterator(source) {
+					if err != nil {
+						errs = append(errs, err)
+
+						continue
+					}
+					values = append(values, v)
+				}
+
+				Expect(values).To(Equal([]int{1, 2}))
+				Expect(errs).To(Equal([]error{testError}))
+			})
+
+			It("should not leak the upstream goroutines when breaking early", func() {
+				err := trxtest.AssertNoLeak(func() {
+					for v, err := range op.ToIterator(op.Range(0, 1000)) {
+						Expect(err).NotTo(HaveOccurred())
+						if v == 3 {
+							break
+						}
+					}
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Drain", func() {
+		Context("when the source mixes values and errors", func() {
+			It("should report the counts and the first error", func() {
+				firstError := errors.New("first error")
+				secondError := errors.New("second error")
+				source := make(chan trx.Result[int], 5)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](firstError)
+				source <- trx.Ok(2)
+				source <- trx.Err[int](secondError)
+				source <- trx.Ok(3)
+				close(source)
+
+				results := make([]trx.Result[trx.DrainReport], 0)
+				for result := range op.Drain(source) {
+					results = append(results, result)
+				}
+
+				Expect(results).To(HaveLen(1))
+				report := results[0].Unwrap()
+				Expect(report.Count).To(Equal(3))
+				Expect(report.Errors).To(Equal(2))
+				Expect(report.FirstError).To(Equal(firstError))
+			})
+		})
+
+		Context("when the source takes time to complete", func() {
+			It("should report the duration measured by the clock", func() {
+				clock := newFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+				source := make(chan trx.Result[int])
+				out := op.Drain(source, op.WithClock(clock))
+
+				source <- trx.Ok(1)
+				clock.Advance(3 * time.Second)
+				close(source)
+
+				result := <-out
+				report := result.Unwrap()
+				Expect(report.Count).To(Equal(1))
+				Expect(report.FirstError).To(BeNil())
+				Expect(report.Duration).To(Equal(3 * time.Second))
+			})
+		})
+	})
+})
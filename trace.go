@@ -0,0 +1,125 @@
+package trx
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceEventKind identifies what happened in a recorded stream event.
+type TraceEventKind int
+
+const (
+	TraceValue    TraceEventKind = iota // A value was emitted
+	TraceError                          // An error was emitted
+	TraceComplete                       // The stream completed
+)
+
+// TraceEvent is a single timestamped event captured by a TraceRecorder.
+type TraceEvent struct {
+	Kind  TraceEventKind
+	Time  time.Time
+	Value any   // The emitted value, set for TraceValue events
+	Err   error // The emitted error, set for TraceError events
+}
+
+// TraceRecorder collects timestamped stream events, typically from op.Trace, so the temporal shape
+// of a stream can be inspected or rendered as a marble diagram. It is safe for concurrent use.
+// Use NewTraceRecorder to create one; the recording starts at creation time.
+type TraceRecorder struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	start  time.Time
+	events []TraceEvent
+}
+
+// NewTraceRecorder creates an empty TraceRecorder whose timeline starts now.
+func NewTraceRecorder() *TraceRecorder {
+	return NewTraceRecorderWithClock(time.Now)
+}
+
+// NewTraceRecorderWithClock creates an empty TraceRecorder that reads the time from now instead of the
+// wall clock, so that tests driving a pipeline with a fake clock get a deterministic timeline.
+// The timeline starts at now().
+func NewTraceRecorderWithClock(now func() time.Time) *TraceRecorder {
+	return &TraceRecorder{now: now, start: now()}
+}
+
+// RecordValue records the emission of a value.
+func (t *TraceRecorder) RecordValue(v any) {
+	t.record(TraceEvent{Kind: TraceValue, Value: v})
+}
+
+// RecordError records the emission of an error.
+func (t *TraceRecorder) RecordError(err error) {
+	t.record(TraceEvent{Kind: TraceError, Err: err})
+}
+
+// RecordComplete records the completion of the stream.
+func (t *TraceRecorder) RecordComplete() {
+	t.record(TraceEvent{Kind: TraceComplete})
+}
+
+func (t *TraceRecorder) record(e TraceEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e.Time = t.now()
+	t.events = append(t.events, e)
+}
+
+// Events returns a copy of the recorded events in the order they happened.
+func (t *TraceRecorder) Events() []TraceEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]TraceEvent(nil), t.events...)
+}
+
+// Marble renders the recorded events as a marble diagram where each character is a time frame of
+// the given duration. Empty frames are drawn as '-', values with their fmt representation, errors as '#'
+// and completion as '|'. Several events falling into the same frame are grouped in parentheses.
+// Example: "-0-1-(2|)" for a stream that emitted 0, 1 and 2 every two frames and then completed.
+func (t *TraceRecorder) Marble(frame time.Duration) string {
+	events := t.Events()
+	if len(events) == 0 || frame <= 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	current := 0
+	for i := 0; i < len(events); {
+		slot := int(events[i].Time.Sub(t.start) / frame)
+		for ; current < slot; current++ {
+			sb.WriteByte('-')
+		}
+
+		group := make([]string, 0, 1)
+		for ; i < len(events) && int(events[i].Time.Sub(t.start)/frame) == slot; i++ {
+			group = append(group, marbleSymbol(events[i]))
+		}
+
+		if len(group) == 1 {
+			sb.WriteString(group[0])
+		} else {
+			sb.WriteString("(" + strings.Join(group, "") + ")")
+		}
+
+		current = slot + 1
+	}
+
+	return sb.String()
+}
+
+func marbleSymbol(e TraceEvent) string {
+	switch e.Kind {
+	case TraceError:
+		return "#"
+	case TraceComplete:
+		return "|"
+	default:
+		return fmt.Sprint(e.Value)
+	}
+}
@@ -1,6 +1,8 @@
 // Package trx provides utilities for handling Go channel
 package trx
 
+import "fmt"
+
 // Result represents a value that can either be successful (Ok) or contain an error (Err).
 // It is a generic type similar to Rust's Result enum, providing safe error handling
 // without using exceptions. The zero value is not useful; use Ok() or Err() constructors.
@@ -56,6 +58,26 @@ func (r *Result[T]) UnwrapOrElse(f func(error) T) T {
 	return r.v
 }
 
+// Or returns the Result itself if it is Ok, otherwise returns the alternative alt.
+// This allows fallback chains such as primary.Or(cached).
+func (r *Result[T]) Or(alt Result[T]) Result[T] {
+	if r.err != nil {
+		return alt
+	}
+
+	return *r
+}
+
+// OrElse returns the Result itself if it is Ok, otherwise calls the provided function
+// with the error and returns the Result it computes. The alternative is only built when needed.
+func (r *Result[T]) OrElse(f func(error) Result[T]) Result[T] {
+	if r.err != nil {
+		return f(r.err)
+	}
+
+	return *r
+}
+
 // Err returns the error from the Result, or nil if the Result is Ok.
 func (r *Result[T]) Err() error {
 	return r.err
@@ -86,3 +108,38 @@ func Map[T, U any](r Result[T], mapper func(T) (U, error)) Result[U] {
 
 	return Ok(mapped)
 }
+
+// CloneWith returns a copy of r whose success value is produced by clone, so the copy shares no
+// memory with the original, e.g. when batches share a backing array. An Err result is returned unchanged.
+// Example: isolated := CloneWith(batch, slices.Clone)
+func CloneWith[T any](r Result[T], clone func(T) T) Result[T] {
+	if r.err != nil {
+		return r
+	}
+
+	return Ok(clone(r.v))
+}
+
+// Try calls fn and converts its outcome into a Result. If fn panics, the panic is recovered
+// and returned as an Err result; a panic value that is an error is wrapped so errors.Is still matches it.
+// Example: result := Try(func() (int, error) { return strconv.Atoi(s) })
+func Try[T any](fn func() (T, error)) (result Result[T]) {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(error); ok {
+				result = Err[T](fmt.Errorf("recovered from panic: %w", err))
+
+				return
+			}
+
+			result = Err[T](fmt.Errorf("recovered from panic: %v", r))
+		}
+	}()
+
+	v, err := fn()
+	if err != nil {
+		return Err[T](err)
+	}
+
+	return Ok(v)
+}
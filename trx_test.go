@@ -2,6 +2,7 @@ package trx_test
 
 import (
 	"errors"
+	"slices"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -116,6 +117,77 @@ var _ = Describe("Result", func() {
 		})
 	})
 
+	Describe("Or method", func() {
+		Context("when the result is Ok", func() {
+			It("should return itself", func() {
+				result := trx.Ok(42)
+				or := result.Or(trx.Ok(10))
+
+				Expect(or.Unwrap()).To(Equal(42))
+			})
+		})
+
+		Context("when the result is Err", func() {
+			It("should return an Ok alternative", func() {
+				result := trx.Err[int](errors.New("test error"))
+				or := result.Or(trx.Ok(10))
+
+				Expect(or.IsOk()).To(BeTrue())
+				Expect(or.Unwrap()).To(Equal(10))
+			})
+
+			It("should return an Err alternative", func() {
+				altErr := errors.New("alt error")
+				result := trx.Err[int](errors.New("test error"))
+				or := result.Or(trx.Err[int](altErr))
+
+				Expect(or.IsErr()).To(BeTrue())
+				Expect(or.Err()).To(Equal(altErr))
+			})
+		})
+	})
+
+	Describe("OrElse method", func() {
+		Context("when the result is Ok", func() {
+			It("should return itself without calling the function", func() {
+				result := trx.Ok(42)
+				called := false
+				orElse := result.OrElse(func(err error) trx.Result[int] {
+					called = true
+					return trx.Ok(10)
+				})
+
+				Expect(orElse.Unwrap()).To(Equal(42))
+				Expect(called).To(BeFalse())
+			})
+		})
+
+		Context("when the result is Err", func() {
+			It("should compute the alternative from the error", func() {
+				testErr := errors.New("test error")
+				result := trx.Err[int](testErr)
+
+				orElse := result.OrElse(func(err error) trx.Result[int] {
+					Expect(err).To(Equal(testErr))
+					return trx.Ok(99)
+				})
+
+				Expect(orElse.Unwrap()).To(Equal(99))
+			})
+
+			It("should return an Err alternative", func() {
+				altErr := errors.New("alt error")
+				result := trx.Err[int](errors.New("test error"))
+
+				orElse := result.OrElse(func(err error) trx.Result[int] {
+					return trx.Err[int](altErr)
+				})
+
+				Expect(orElse.Err()).To(Equal(altErr))
+			})
+		})
+	})
+
 	Describe("Map function", func() {
 		Context("when mapping an Ok result", func() {
 			It("should apply the mapper function and return Ok result", func() {
@@ -162,6 +234,67 @@ var _ = Describe("Result", func() {
 		})
 	})
 
+	Describe("CloneWith function", func() {
+		Context("when cloning an Ok result", func() {
+			It("should not share the value with the original", func() {
+				original := trx.Ok([]int{1, 2, 3})
+				clone := trx.CloneWith(original, slices.Clone)
+
+				cloned := clone.Unwrap()
+				cloned[0] = 100
+
+				Expect(original.Unwrap()).To(Equal([]int{1, 2, 3}))
+				Expect(clone.Unwrap()).To(Equal([]int{100, 2, 3}))
+			})
+		})
+
+		Context("when cloning an Err result", func() {
+			It("should return the error without calling clone", func() {
+				originalErr := errors.New("original error")
+				called := false
+
+				clone := trx.CloneWith(trx.Err[[]int](originalErr), func(v []int) []int {
+					called = true
+					return v
+				})
+
+				Expect(clone.Err()).To(Equal(originalErr))
+				Expect(called).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Try function", func() {
+		Context("when the function succeeds", func() {
+			It("should return an Ok result", func() {
+				result := trx.Try(func() (int, error) { return 42, nil })
+				Expect(result.Unwrap()).To(Equal(42))
+			})
+		})
+
+		Context("when the function returns an error", func() {
+			It("should return an Err result with the error", func() {
+				testErr := errors.New("test error")
+				result := trx.Try(func() (int, error) { return 0, testErr })
+				Expect(result.Err()).To(Equal(testErr))
+			})
+		})
+
+		Context("when the function panics", func() {
+			It("should recover and return an Err result", func() {
+				result := trx.Try(func() (int, error) { panic("boom") })
+				Expect(result.IsErr()).To(BeTrue())
+				Expect(result.Err().Error()).To(ContainSubstring("boom"))
+			})
+
+			It("should wrap a panic error value", func() {
+				testErr := errors.New("test error")
+				result := trx.Try(func() (int, error) { panic(testErr) })
+				Expect(errors.Is(result.Err(), testErr)).To(BeTrue())
+			})
+		})
+	})
+
 	Describe("Edge cases", func() {
 		Context("with nil values", func() {
 			It("should handle nil pointers correctly", func() {
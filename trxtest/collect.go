@@ -0,0 +1,68 @@
+package trxtest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/foreveralonet/trx"
+)
+
+// ErrTimeout is returned by Collect when the source does not complete within the given timeout.
+var ErrTimeout = errors.New("trxtest: timed out waiting for the stream to complete")
+
+type collectConfig struct {
+	maxItems int // Stop after this many items (0 = collect until the source closes)
+}
+
+// CollectOption configures the behavior of Collect.
+type CollectOption func(*collectConfig)
+
+// WithMaxItems stops collecting once n items have been received, without waiting for the source to close.
+// Values less than or equal to 0 are ignored.
+//
+// Example:
+//
+//	Collect(out, time.Second, WithMaxItems(3)) // Returns after the first 3 items
+func WithMaxItems(n int) CollectOption {
+	return func(c *collectConfig) {
+		if n > 0 {
+			c.maxItems = n
+		}
+	}
+}
+
+// Collect drains the source channel into a slice. It returns an error wrapping ErrTimeout, together with
+// the items received so far, if the source does not complete within timeout, preventing a test from
+// hanging on a stream that never closes.
+//
+// Example usage:
+//
+//	results, err := Collect(out, time.Second)
+func Collect[T any](source <-chan trx.Result[T], timeout time.Duration, options ...CollectOption) ([]trx.Result[T], error) {
+	conf := &collectConfig{}
+	for _, opt := range options {
+		opt(conf)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	results := make([]trx.Result[T], 0)
+	for {
+		if conf.maxItems > 0 && len(results) >= conf.maxItems {
+			return results, nil
+		}
+
+		select {
+		case <-timer.C:
+			return results, fmt.Errorf("%w after %s (%d items received)", ErrTimeout, timeout, len(results))
+		case r, ok := <-source:
+			if !ok {
+				return results, nil
+			}
+
+			results = append(results, r)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package trxtest
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// leakTimeout is how long AssertNoLeak waits for goroutines started by fn to exit.
+const leakTimeout = time.Second
+
+// AssertNoLeak runs fn, typically a whole pipeline that is drained to completion, and returns an error
+// listing the goroutines started while fn ran that are still running shortly afterwards. Goroutines are
+// given up to one second to exit, so pipelines that shut down asynchronously pass. Goroutines started
+// concurrently by unrelated code can cause false positives, so avoid running it in parallel with other tests.
+//
+// Example usage:
+//
+//	err := AssertNoLeak(func() {
+//	    for range op.Map(op.Range(0, 10), mapper, op.WithPoolSize(4)) {
+//	    }
+//	})
+func AssertNoLeak(fn func()) error {
+	before := goroutines()
+
+	fn()
+
+	deadline := time.Now().Add(leakTimeout)
+	for {
+		leaked := make([]string, 0)
+		for id, stack := range goroutines() {
+			if _, ok := before[id]; !ok {
+				leaked = append(leaked, stack)
+			}
+		}
+
+		if len(leaked) == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("trxtest: %d goroutine(s) leaked\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// goroutines returns the stacks of all running goroutines keyed by their header line
+// ("goroutine N"), which identifies a goroutine for its whole lifetime.
+func goroutines() map[string]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+
+			break
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := make(map[string]string)
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		header, _, _ := strings.Cut(string(stack), " [")
+		stacks[header] = string(stack)
+	}
+
+	return stacks
+}
@@ -0,0 +1,48 @@
+// Package trxtest provides helpers for testing code built on trx channels.
+// The helpers drain streams and report problems as plain errors so they can be
+// used with any testing framework.
+package trxtest
+
+import (
+	"fmt"
+
+	"github.com/foreveralonet/trx"
+)
+
+// AssertOrdered drains the source channel and returns an error if its values are not in the order
+// defined by less. Two consecutive values a and b are out of order when less(b, a) is true, so equal
+// values are accepted. If the source emits an error result, that error is returned instead.
+// The source is always drained completely, even when a problem is found.
+//
+// Example usage:
+//
+//	err := AssertOrdered(out, func(a, b int) bool { return a < b })
+func AssertOrdered[T any](source <-chan trx.Result[T], less func(a, b T) bool) error {
+	var (
+		prev     T
+		firstErr error
+	)
+
+	index := 0
+	for r := range source {
+		if firstErr != nil {
+			continue
+		}
+
+		value, err := r.Get()
+		if err != nil {
+			firstErr = fmt.Errorf("trxtest: error result at index %d: %w", index, err)
+
+			continue
+		}
+
+		if index > 0 && less(value, prev) {
+			firstErr = fmt.Errorf("trxtest: value %v at index %d is out of order after %v", value, index, prev)
+		}
+
+		prev = value
+		index++
+	}
+
+	return firstErr
+}
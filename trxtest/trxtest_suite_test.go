@@ -0,0 +1,13 @@
+package trxtest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTrxtest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TRXTEST Suite")
+}
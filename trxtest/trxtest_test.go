@@ -0,0 +1,228 @@
+package trxtest_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/foreveralonet/trx"
+	"github.com/foreveralonet/trx/op"
+	"github.com/foreveralonet/trx/trxtest"
+)
+
+var _ = Describe("Testing helpers", func() {
+
+	Describe("AssertOrdered", func() {
+		less := func(a, b int) bool { return a < b }
+
+		Context("when the stream is ordered", func() {
+			It("should return nil", func() {
+				err := trxtest.AssertOrdered(op.FormSlice([]int{1, 2, 2, 5}), less)
+				Expect(err).To(BeNil())
+			})
+
+			It("should accept an empty stream", func() {
+				err := trxtest.AssertOrdered(op.Range(0, 0), less)
+				Expect(err).To(BeNil())
+			})
+
+			It("should validate a serialized concurrent map", func() {
+				out := op.Map(op.Range(0, 20), func(v int, _ int) (int, error) {
+					return v, nil
+				}, op.WithPoolSize(4), op.WithSerialize())
+
+				Expect(trxtest.AssertOrdered(out, less)).To(Succeed())
+			})
+		})
+
+		Context("when the stream is not ordered", func() {
+			It("should return an error", func() {
+				err := trxtest.AssertOrdered(op.FormSlice([]int{1, 3, 2}), less)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("index 2"))
+			})
+		})
+
+		Context("when the stream contains an error", func() {
+			It("should return the wrapped error", func() {
+				testError := errors.New("source error")
+				source := make(chan trx.Result[int], 2)
+				source <- trx.Ok(1)
+				source <- trx.Err[int](testError)
+				close(source)
+
+				err := trxtest.AssertOrdered(source, less)
+				Expect(errors.Is(err, testError)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("Collect", func() {
+		Context("when the stream completes in time", func() {
+			It("should return all results", func() {
+				results, err := trxtest.Collect(op.Range(0, 3), time.Second)
+				Expect(err).To(BeNil())
+				Expect(results).To(HaveLen(3))
+				Expect(results[2].Unwrap()).To(Equal(2))
+			})
+
+			It("should stop after the maximum number of items", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				results, err := trxtest.Collect(op.Interval(5*time.Millisecond, op.WithContext(ctx)), time.Second, trxtest.WithMaxItems(2))
+				Expect(err).To(BeNil())
+				Expect(results).To(HaveLen(2))
+			})
+		})
+
+		Context("when the stream does not complete in time", func() {
+			It("should return a timeout error with the partial results", func() {
+				source := make(chan trx.Result[int], 1)
+				source <- trx.Ok(1)
+
+				results, err := trxtest.Collect(source, 20*time.Millisecond)
+				Expect(errors.Is(err, trxtest.ErrTimeout)).To(BeTrue())
+				Expect(results).To(HaveLen(1))
+			})
+		})
+	})
+
+	Describe("AssertNoLeak", func() {
+		Context("when every goroutine exits", func() {
+			It("should return nil for a pooled map", func() {
+				err := trxtest.AssertNoLeak(func() {
+					out := op.Map(op.Range(0, 50), func(v int, _ int) (int, error) {
+						return v, nil
+					}, op.WithPoolSize(4))
+
+					for range out {
+					}
+				})
+
+				Expect(err).To(BeNil())
+			})
+
+			It("should return nil for merged streams", func() {
+				err := trxtest.AssertNoLeak(func() {
+					for range op.MergePriority(op.Range(0, 10), op.Range(10, 10)) {
+					}
+
+					for range op.MergeMapped(op.Range(0, 10), func(v int) int { return v }, op.Range(10, 10)) {
+					}
+				})
+
+				Expect(err).To(BeNil())
+			})
+
+			It("should return nil for operators relaying an inner stream", func() {
+				err := trxtest.AssertNoLeak(func() {
+					out, _ := op.MapWithProgress(op.Range(0, 10), 10, func(v int, _ int) (int, error) {
+						return v, nil
+					})
+					for range out {
+					}
+
+					for range op.MapAsync(op.Range(0, 10), func(v int, _ int) <-chan trx.Result[int] {
+						return op.FormSlice([]int{v})
+					}, op.WithPoolSize(3)) {
+					}
+				})
+
+				Expect(err).To(BeNil())
+			})
+
+			It("should return nil for a flat-mapped stream", func() {
+				err := trxtest.AssertNoLeak(func() {
+					for range op.FlatMap(op.Range(0, 10), func(v int, _ int) <-chan trx.Result[int] {
+						return op.Range(v, 3)
+					}, op.WithPoolSize(4)) {
+					}
+				})
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("when the consumer stops reading and cancels the context", func() {
+			// abandon reads a single result from out, then cancels the pipeline without reading further.
+			abandon := func(cancel context.CancelFunc, out <-chan trx.Result[int]) {
+				<-out
+				cancel()
+			}
+
+			It("should return nil for merged streams", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					abandon(cancel, op.MergeMapped(op.Range(0, 100, op.WithContext(ctx)), func(v int) int { return v },
+						op.Range(0, 100, op.WithContext(ctx)), op.WithContext(ctx)))
+				})
+
+				Expect(err).To(BeNil())
+			})
+
+			It("should return nil for a flat-mapped stream", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					abandon(cancel, op.FlatMap(op.Range(0, 100, op.WithContext(ctx)), func(v int, _ int) <-chan trx.Result[int] {
+						return op.Range(v, 100, op.WithContext(ctx))
+					}, op.WithPoolSize(4), op.WithContext(ctx)))
+				})
+
+				Expect(err).To(BeNil())
+			})
+
+			It("should return nil for operators relaying an inner stream", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					out, _ := op.MapWithProgress(op.Range(0, 100, op.WithContext(ctx)), 100, func(v int, _ int) (int, error) {
+						return v, nil
+					}, op.WithContext(ctx))
+					abandon(cancel, out)
+				})
+
+				Expect(err).To(BeNil())
+			})
+
+			It("should return nil for a partitioned stream forwarding errors", func() {
+				err := trxtest.AssertNoLeak(func() {
+					ctx, cancel := context.WithCancel(context.Background())
+
+					source := make(chan trx.Result[int], 2)
+					source <- trx.Err[int](errors.New("first"))
+					source <- trx.Err[int](errors.New("second"))
+					close(source)
+
+					abandon(cancel, op.GroupByParallel(source, func(v int) int { return v % 2 },
+						func(_ int, values <-chan trx.Result[int]) <-chan trx.Result[int] {
+							return values
+						}, op.WithContext(ctx)))
+				})
+
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("when a goroutine is left running", func() {
+			It("should return an error", func() {
+				release := make(chan struct{})
+				defer close(release)
+
+				err := trxtest.AssertNoLeak(func() {
+					go func() {
+						<-release
+					}()
+				})
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("leaked"))
+			})
+		})
+	})
+})
@@ -0,0 +1,43 @@
+package trx
+
+import "time"
+
+// Indexed pairs a value with its position in a stream.
+type Indexed[T any] struct {
+	Index int // The zero-based position of the value
+	Value T   // The value itself
+}
+
+// Step pairs the accumulated state of a running computation with the input that produced it.
+type Step[A, T any] struct {
+	State A // The accumulated state after applying Input
+	Input T // The input value that produced State
+}
+
+// DeadLetter pairs an input value with the error that made its processing fail, so it can be
+// inspected or reprocessed later.
+type DeadLetter[T any] struct {
+	Value T     // The input value that failed
+	Err   error // The error returned while processing Value
+}
+
+// Pair holds the two values yielded together by an iter.Seq2, such as a key and its value.
+type Pair[K, V any] struct {
+	Key   K // The first value of the pair
+	Value V // The second value of the pair
+}
+
+// TimedBatch is a batch of values together with the time window it covers.
+type TimedBatch[T any] struct {
+	Items []T       // The values collected during the window
+	Start time.Time // The start of the window
+	End   time.Time // The end of the window
+}
+
+// DrainReport summarizes a stream that was consumed without keeping its values.
+type DrainReport struct {
+	Count      int           // The number of successful values
+	Errors     int           // The number of errors
+	FirstError error         // The first error received, or nil
+	Duration   time.Duration // The time spent consuming the stream
+}